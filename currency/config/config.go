@@ -0,0 +1,626 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/server"
+	"github.com/kahvecikaan/buildingMicroservices/currency/tlsconfig"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Duration is a time.Duration written as a string such as "5s" in config files
+type Duration time.Duration
+
+// MarshalJSON encodes d in time.Duration string format
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON accepts a duration string such as "1m30s"
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"5s\": %w", err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Config is the complete configuration of the currency service
+type Config struct {
+	// BindAddress is the address the gRPC server listens on
+	BindAddress string `json:"bind_address"`
+	// MetricsAddress is the address of the HTTP listener serving /metrics, empty disables it
+	MetricsAddress string `json:"metrics_address"`
+	// GatewayAddress is the address of the HTTP/JSON gateway, empty disables it
+	GatewayAddress string `json:"gateway_address"`
+	// LogLevel is the minimum level logged [trace, debug, info, warn, error], reloadable
+	LogLevel string `json:"log_level"`
+
+	TLS           TLSConfig           `json:"tls"`
+	Auth          AuthConfig          `json:"auth"`
+	Rates         RatesConfig         `json:"rates"`
+	History       HistoryConfig       `json:"history"`
+	Subscriptions SubscriptionsConfig `json:"subscriptions"`
+}
+
+// TLSConfig configures TLS on the gRPC listener, it is served in plaintext without a certificate
+type TLSConfig struct {
+	// CertFile and KeyFile are the PEM encoded server certificate and key
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	// ClientCAFile enables verification of client certificates issued by these CAs
+	ClientCAFile string `json:"client_ca_file"`
+	// RequireClientCert rejects clients without a valid certificate
+	RequireClientCert bool `json:"require_client_cert"`
+	// ReloadInterval is how often the files are checked for rotation
+	ReloadInterval Duration `json:"reload_interval"`
+}
+
+// Enabled reports whether the listener serves TLS
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// AuthConfig configures client authentication, every client may call the service
+// without a credentials file
+type AuthConfig struct {
+	// CredentialsFile is the JSON file of the clients, their key hashes and limits.
+	// It is read again on SIGHUP.
+	CredentialsFile string `json:"credentials_file"`
+}
+
+// RatesConfig configures where rates come from and how they are updated
+type RatesConfig struct {
+	// Provider is the source of exchange rates [ecb, file, static]
+	Provider string `json:"provider"`
+	// Source is the URL for the ecb provider or the path for the file provider
+	Source string `json:"source"`
+	// Base is the currency the rates of the provider are quoted against
+	Base string `json:"base"`
+	// Fallbacks are tried in order when provider fails
+	Fallbacks []ProviderConfig `json:"fallbacks"`
+	// ProviderTimeout is how long a provider may take before the next one is tried
+	ProviderTimeout Duration `json:"provider_timeout"`
+	// Sources are further providers whose quotes are combined with those of provider
+	Sources []SourceConfig `json:"sources"`
+	// Aggregation combines the quotes of several sources for a pair [median, weighted]
+	Aggregation string `json:"aggregation"`
+	// Pivot is the currency rates are kept against for snapshots, candles and simulate mode
+	Pivot string `json:"pivot"`
+	// MaxAge is how long live rates are fresh after the last fetch, older rates
+	// are reported as stale and the health service reports NOT_SERVING. 0 disables
+	// the check.
+	MaxAge Duration `json:"max_age"`
+	// MaxChangePercent is the largest change of a fetched rate against the current
+	// one that is accepted, 0 accepts any change
+	MaxChangePercent float64 `json:"max_change_percent"`
+	// ConfirmChange is the number of fetches or sources in a row that must report
+	// a rate beyond max_change_percent before it is accepted, 0 never accepts it
+	ConfirmChange int `json:"confirm_change"`
+	// MonitorMode is how rates are updated after startup [simulate, live, replay]
+	MonitorMode string `json:"monitor_mode"`
+	// MonitorInterval is the time between two updates, reloadable
+	MonitorInterval Duration `json:"monitor_interval"`
+	// Model moves the rates in simulate mode [uniform, gbm]
+	Model string `json:"model"`
+	// Volatility is the maximum relative change per tick of the uniform model, reloadable
+	Volatility float64 `json:"volatility"`
+	// Drift and AnnualVolatility are the annualised parameters of the gbm model for
+	// every currency missing from CurrencyModels
+	Drift            float64 `json:"drift"`
+	AnnualVolatility float64 `json:"annual_volatility"`
+	// CurrencyModels are the gbm parameters of single currencies by code
+	CurrencyModels map[string]CurrencyModel `json:"currency_models"`
+	// Seed seeds simulate mode, 0 uses a time based seed
+	Seed int64 `json:"seed"`
+	// TapePath is the file the published rates are recorded to, empty disables recording
+	TapePath string `json:"tape_path"`
+	// ReplayTape is the tape published in replay mode
+	ReplayTape string `json:"replay_tape"`
+	// ReplaySpeed divides the recorded delays of the tape, 1 is the original speed
+	ReplaySpeed float64 `json:"replay_speed"`
+	// MaxBackoff caps the delay between retries while the provider fails
+	MaxBackoff Duration `json:"max_backoff"`
+	// SnapshotPath is the file the last fetched rates are saved to, empty disables snapshots
+	SnapshotPath string `json:"snapshot_path"`
+	// ReplaySize is the number of recent snapshots kept to replay missed updates
+	ReplaySize int `json:"replay_size"`
+	// SeriesSize is the number of snapshots recorded for candles, 0 disables candles
+	SeriesSize int `json:"series_size"`
+	// SeriesRetention is how long snapshots are recorded for candles, 0 keeps them
+	// until series_size is reached
+	SeriesRetention Duration `json:"series_retention"`
+}
+
+// ProviderConfig configures a rate provider
+type ProviderConfig struct {
+	// Name identifies the provider in logs and responses, it defaults to the provider
+	Name string `json:"name"`
+	// Provider is the kind of provider [ecb, file, static]
+	Provider string `json:"provider"`
+	// Source is the URL for the ecb provider or the path for the file provider
+	Source string `json:"source"`
+	// Base is the currency the rates of the provider are quoted against
+	Base string `json:"base"`
+}
+
+// name returns the name of the provider
+func (p ProviderConfig) name() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Provider
+}
+
+// SourceConfig configures a rate provider in addition to rates.provider
+type SourceConfig struct {
+	ProviderConfig
+	// Weight is the weight of the quotes of the source in weighted aggregation
+	Weight float64 `json:"weight"`
+}
+
+// CurrencyModel is the annualised drift and volatility of a currency in the gbm model
+type CurrencyModel struct {
+	Drift      float64 `json:"drift"`
+	Volatility float64 `json:"volatility"`
+}
+
+// HistoryConfig configures the historical rates
+type HistoryConfig struct {
+	// Source is the URL or path of an ECB history document
+	Source string `json:"source"`
+	// RefreshInterval is how often the source is loaded again for new publications
+	RefreshInterval Duration `json:"refresh_interval"`
+}
+
+// SubscriptionsConfig configures SubscribeRates streams
+type SubscriptionsConfig struct {
+	// QueueSize is the number of messages buffered per subscriber
+	QueueSize int `json:"queue_size"`
+	// Overflow is what happens when a subscriber falls behind [coalesce, disconnect]
+	Overflow string `json:"overflow"`
+	// CleanupInterval is how often inactive subscriptions are looked for
+	CleanupInterval Duration `json:"cleanup_interval"`
+	// StaleTimeout is how long a subscription can be inactive before it is removed
+	StaleTimeout Duration `json:"stale_timeout"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() *Config {
+	rates := data.DefaultRatesOptions()
+	srv := server.DefaultOptions()
+
+	return &Config{
+		BindAddress:    ":9092",
+		MetricsAddress: ":9093",
+		GatewayAddress: ":9094",
+		LogLevel:       "debug",
+		TLS: TLSConfig{
+			ReloadInterval: Duration(tlsconfig.DefaultReloadInterval),
+		},
+		Rates: RatesConfig{
+			Provider:         "ecb",
+			Base:             "EUR",
+			Aggregation:      string(rates.Aggregation),
+			Pivot:            rates.Pivot,
+			ProviderTimeout:  Duration(10 * time.Second),
+			MaxAge:           Duration(rates.MaxAge),
+			MaxChangePercent: rates.MaxChange * 100,
+			ConfirmChange:    rates.ConfirmChange,
+			MonitorMode:      string(rates.Monitor.Mode),
+			MonitorInterval:  Duration(rates.Monitor.Interval),
+			Model:            string(data.ModelUniform),
+			Volatility:       rates.Monitor.Volatility,
+			AnnualVolatility: 0.1,
+			ReplaySpeed:      1,
+			MaxBackoff:       Duration(rates.Monitor.MaxBackoff),
+			SnapshotPath:     "./rates-snapshot.json",
+			ReplaySize:       rates.ReplaySize,
+			SeriesSize:       rates.SeriesSize,
+			SeriesRetention:  Duration(rates.SeriesRetention),
+		},
+		History: HistoryConfig{
+			Source:          data.ECBHistory90DayURL,
+			RefreshInterval: Duration(6 * time.Hour),
+		},
+		Subscriptions: SubscriptionsConfig{
+			QueueSize:       srv.Stream.QueueSize,
+			Overflow:        string(srv.Stream.Overflow),
+			CleanupInterval: Duration(srv.CleanupInterval),
+			StaleTimeout:    Duration(srv.StaleTimeout),
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the JSON file at path if path
+// is not empty, and finally the environment variables, and validates the result
+func Load(path string) (*Config, error) {
+	c := Default()
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open config file: %w", err)
+		}
+		defer f.Close()
+
+		d := json.NewDecoder(f)
+		d.DisallowUnknownFields()
+		if err := d.Decode(c); err != nil {
+			return nil, fmt.Errorf("unable to decode config file %s: %w", path, err)
+		}
+	}
+
+	if err := c.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// applyEnv overrides the settings whose environment variable is set
+func (c *Config) applyEnv() error {
+	var errs []error
+	str := func(name string, v *string) {
+		if s, ok := os.LookupEnv(name); ok {
+			*v = s
+		}
+	}
+	integer := func(name string, v *int) {
+		if s, ok := os.LookupEnv(name); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*v = n
+		}
+	}
+	float := func(name string, v *float64) {
+		if s, ok := os.LookupEnv(name); ok {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*v = f
+		}
+	}
+	boolean := func(name string, v *bool) {
+		if s, ok := os.LookupEnv(name); ok {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*v = b
+		}
+	}
+	duration := func(name string, v *Duration) {
+		if s, ok := os.LookupEnv(name); ok {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			*v = Duration(d)
+		}
+	}
+
+	str("BIND_ADDRESS", &c.BindAddress)
+	str("METRICS_ADDRESS", &c.MetricsAddress)
+	str("GATEWAY_ADDRESS", &c.GatewayAddress)
+	str("LOG_LEVEL", &c.LogLevel)
+
+	str("TLS_CERT_FILE", &c.TLS.CertFile)
+	str("TLS_KEY_FILE", &c.TLS.KeyFile)
+	str("TLS_CLIENT_CA_FILE", &c.TLS.ClientCAFile)
+	boolean("TLS_REQUIRE_CLIENT_CERT", &c.TLS.RequireClientCert)
+	duration("TLS_RELOAD_INTERVAL", &c.TLS.ReloadInterval)
+
+	str("AUTH_CREDENTIALS_FILE", &c.Auth.CredentialsFile)
+
+	str("RATE_PROVIDER", &c.Rates.Provider)
+	str("RATE_SOURCE", &c.Rates.Source)
+	str("RATE_BASE", &c.Rates.Base)
+	str("RATE_AGGREGATION", &c.Rates.Aggregation)
+	str("RATE_PIVOT", &c.Rates.Pivot)
+	duration("PROVIDER_TIMEOUT", &c.Rates.ProviderTimeout)
+	duration("RATES_MAX_AGE", &c.Rates.MaxAge)
+	float("RATES_MAX_CHANGE_PERCENT", &c.Rates.MaxChangePercent)
+	integer("RATES_CONFIRM_CHANGE", &c.Rates.ConfirmChange)
+	str("MONITOR_MODE", &c.Rates.MonitorMode)
+	duration("MONITOR_INTERVAL", &c.Rates.MonitorInterval)
+	str("SIM_MODEL", &c.Rates.Model)
+	float("SIM_VOLATILITY", &c.Rates.Volatility)
+	float("SIM_DRIFT", &c.Rates.Drift)
+	float("SIM_ANNUAL_VOLATILITY", &c.Rates.AnnualVolatility)
+	if s, ok := os.LookupEnv("SIM_SEED"); ok {
+		seed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("SIM_SEED: %w", err))
+		} else {
+			c.Rates.Seed = seed
+		}
+	}
+	duration("MAX_BACKOFF", &c.Rates.MaxBackoff)
+	str("SNAPSHOT_PATH", &c.Rates.SnapshotPath)
+	str("TAPE_PATH", &c.Rates.TapePath)
+	str("REPLAY_TAPE", &c.Rates.ReplayTape)
+	float("REPLAY_SPEED", &c.Rates.ReplaySpeed)
+	integer("REPLAY_SIZE", &c.Rates.ReplaySize)
+	integer("SERIES_SIZE", &c.Rates.SeriesSize)
+	duration("SERIES_RETENTION", &c.Rates.SeriesRetention)
+
+	str("HISTORY_SOURCE", &c.History.Source)
+	duration("HISTORY_REFRESH_INTERVAL", &c.History.RefreshInterval)
+
+	integer("STREAM_QUEUE_SIZE", &c.Subscriptions.QueueSize)
+	str("STREAM_OVERFLOW", &c.Subscriptions.Overflow)
+	duration("CLEANUP_INTERVAL", &c.Subscriptions.CleanupInterval)
+	duration("SUBSCRIPTION_TIMEOUT", &c.Subscriptions.StaleTimeout)
+
+	return errors.Join(errs...)
+}
+
+// Validate checks every setting and reports all invalid ones
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.BindAddress != "", "bind_address must not be empty")
+	check(hclog.LevelFromString(c.LogLevel) != hclog.NoLevel, "log_level %q is not a valid level", c.LogLevel)
+
+	if err := c.TLSOptions().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("tls: %w", err))
+	}
+	check(c.TLS.ClientCAFile == "" || c.TLS.Enabled(), "tls.client_ca_file needs tls.cert_file")
+	check(c.TLS.ReloadInterval > 0, "tls.reload_interval must be positive")
+
+	switch c.Rates.Provider {
+	case "ecb", "static":
+	case "file":
+		check(c.Rates.Source != "", "rates.source is required for the file provider")
+	default:
+		errs = append(errs, fmt.Errorf("rates.provider %q is not one of ecb, file, static", c.Rates.Provider))
+	}
+	checkProvider := func(field string, p ProviderConfig) {
+		switch p.Provider {
+		case "ecb", "static":
+		case "file":
+			check(p.Source != "", "%s.source is required for the file provider", field)
+		default:
+			errs = append(errs, fmt.Errorf("%s.provider %q is not one of ecb, file, static", field, p.Provider))
+		}
+	}
+	for i, p := range c.Rates.Fallbacks {
+		checkProvider(fmt.Sprintf("rates.fallbacks[%d]", i), p)
+	}
+	check(c.Rates.ProviderTimeout >= 0, "rates.provider_timeout must not be negative")
+	names := map[string]bool{c.Rates.Provider: true}
+	for i, s := range c.Rates.Sources {
+		checkProvider(fmt.Sprintf("rates.sources[%d]", i), s.ProviderConfig)
+		check(!names[s.name()], "rates.sources[%d].name %q is already used, the name defaults to the provider", i, s.name())
+		names[s.name()] = true
+		check(s.Weight >= 0, "rates.sources[%d].weight must not be negative", i)
+	}
+	if _, err := data.ParseAggregation(c.Rates.Aggregation); err != nil {
+		errs = append(errs, fmt.Errorf("rates.aggregation: %w", err))
+	}
+	check(len(c.Rates.Pivot) == 3, "rates.pivot must be a currency code")
+	check(c.Rates.MaxAge >= 0, "rates.max_age must not be negative")
+	check(c.Rates.MaxChangePercent >= 0, "rates.max_change_percent must not be negative")
+	check(c.Rates.ConfirmChange >= 0, "rates.confirm_change must not be negative")
+	mode, err := data.ParseMonitorMode(c.Rates.MonitorMode)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("rates.monitor_mode: %w", err))
+	}
+	check(mode != data.MonitorReplay || c.Rates.ReplayTape != "", "rates.replay_tape is required for replay mode")
+	check(c.Rates.ReplaySpeed > 0, "rates.replay_speed must be positive")
+	check(c.Rates.MonitorInterval > 0, "rates.monitor_interval must be positive")
+	if _, err := data.ParseModelKind(c.Rates.Model); err != nil {
+		errs = append(errs, fmt.Errorf("rates.model: %w", err))
+	}
+	check(c.Rates.Volatility >= 0 && c.Rates.Volatility < 1, "rates.volatility must be in [0, 1)")
+	check(c.Rates.AnnualVolatility >= 0, "rates.annual_volatility must not be negative")
+	for code, m := range c.Rates.CurrencyModels {
+		check(m.Volatility >= 0, "rates.currency_models.%s.volatility must not be negative", code)
+	}
+	check(c.Rates.MaxBackoff >= 0, "rates.max_backoff must not be negative")
+	check(c.Rates.ReplaySize >= 0, "rates.replay_size must not be negative")
+	check(c.Rates.SeriesSize >= 0, "rates.series_size must not be negative")
+	check(c.Rates.SeriesRetention >= 0, "rates.series_retention must not be negative")
+
+	check(c.History.RefreshInterval > 0, "history.refresh_interval must be positive")
+
+	check(c.Subscriptions.QueueSize > 0, "subscriptions.queue_size must be positive")
+	if _, err := server.ParseOverflowPolicy(c.Subscriptions.Overflow); err != nil {
+		errs = append(errs, fmt.Errorf("subscriptions.overflow: %w", err))
+	}
+	check(c.Subscriptions.CleanupInterval > 0, "subscriptions.cleanup_interval must be positive")
+	check(c.Subscriptions.StaleTimeout > 0, "subscriptions.stale_timeout must be positive")
+
+	return errors.Join(errs...)
+}
+
+// TLSOptions returns the options for the reloader of the server certificates
+func (c *Config) TLSOptions() tlsconfig.Options {
+	return tlsconfig.Options{
+		CertFile:          c.TLS.CertFile,
+		KeyFile:           c.TLS.KeyFile,
+		CAFile:            c.TLS.ClientCAFile,
+		RequireClientCert: c.TLS.RequireClientCert,
+		ReloadInterval:    time.Duration(c.TLS.ReloadInterval),
+	}
+}
+
+// RatesOptions returns the options for data.NewRates, c must be valid
+func (c *Config) RatesOptions() data.RatesOptions {
+	opts := data.DefaultRatesOptions()
+	opts.Monitor = c.MonitorOptions()
+	opts.SnapshotPath = c.Rates.SnapshotPath
+	opts.ReplaySize = c.Rates.ReplaySize
+	opts.SeriesSize = c.Rates.SeriesSize
+	opts.SeriesRetention = time.Duration(c.Rates.SeriesRetention)
+	opts.TapePath = c.Rates.TapePath
+	opts.Pivot = c.Rates.Pivot
+	opts.Aggregation, _ = data.ParseAggregation(c.Rates.Aggregation)
+	opts.MaxAge = time.Duration(c.Rates.MaxAge)
+	opts.MaxChange = c.Rates.MaxChangePercent / 100
+	opts.ConfirmChange = c.Rates.ConfirmChange
+	return opts
+}
+
+// RateSources creates the sources of rates.provider and rates.sources, the first
+// is named after its provider and fails over to rates.fallbacks. Every provider
+// is given up on after rates.provider_timeout. c must be valid.
+func (c *Config) RateSources(l hclog.Logger) ([]data.Source, error) {
+	primary := ProviderConfig{Provider: c.Rates.Provider, Source: c.Rates.Source, Base: c.Rates.Base}
+	timeout := time.Duration(c.Rates.ProviderTimeout)
+
+	failover, err := newFailover(l, timeout, append([]ProviderConfig{primary}, c.Rates.Fallbacks...))
+	if err != nil {
+		return nil, err
+	}
+	sources := []data.Source{{Name: primary.name(), Provider: failover}}
+
+	for _, s := range c.Rates.Sources {
+		provider, err := newFailover(l, timeout, []ProviderConfig{s.ProviderConfig})
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, data.Source{Name: s.name(), Provider: provider, Weight: s.Weight})
+	}
+	return sources, nil
+}
+
+// newFailover creates a provider trying the providers of configs in order
+func newFailover(l hclog.Logger, timeout time.Duration, configs []ProviderConfig) (*data.FailoverProvider, error) {
+	providers := make([]data.Source, len(configs))
+	for i, p := range configs {
+		provider, err := data.NewProvider(p.Provider, p.Source)
+		if err != nil {
+			return nil, fmt.Errorf("provider %s: %w", p.name(), err)
+		}
+		providers[i] = data.Source{Name: p.name(), Provider: provider, Base: p.Base}
+	}
+	return data.NewFailoverProvider(l, timeout, providers...), nil
+}
+
+// MonitorOptions returns the options for the rate monitor, c must be valid
+func (c *Config) MonitorOptions() data.MonitorOptions {
+	mode, _ := data.ParseMonitorMode(c.Rates.MonitorMode)
+	return data.MonitorOptions{
+		Mode:       mode,
+		Interval:   time.Duration(c.Rates.MonitorInterval),
+		Model:      c.SimulationModel(),
+		Volatility: c.Rates.Volatility,
+		Seed:       c.Rates.Seed,
+		Tape:       c.Rates.ReplayTape,
+		Speed:      c.Rates.ReplaySpeed,
+		MaxBackoff: time.Duration(c.Rates.MaxBackoff),
+	}
+}
+
+// SimulationModel returns the model of simulate mode, the uniform model is nil
+// so the monitor keeps applying the reloadable volatility. c must be valid.
+func (c *Config) SimulationModel() data.SimulationModel {
+	if kind, _ := data.ParseModelKind(c.Rates.Model); kind != data.ModelGBM {
+		return nil
+	}
+
+	model := data.GBM{
+		Default:    data.GBMParams{Drift: c.Rates.Drift, Volatility: c.Rates.AnnualVolatility},
+		Currencies: make(map[string]data.GBMParams, len(c.Rates.CurrencyModels)),
+	}
+	for code, m := range c.Rates.CurrencyModels {
+		model.Currencies[code] = data.GBMParams{Drift: m.Drift, Volatility: m.Volatility}
+	}
+	return model
+}
+
+// ServerOptions returns the options for server.NewCurrency, c must be valid
+func (c *Config) ServerOptions() server.Options {
+	overflow, _ := server.ParseOverflowPolicy(c.Subscriptions.Overflow)
+	return server.Options{
+		Stream: server.StreamOptions{
+			QueueSize: c.Subscriptions.QueueSize,
+			Overflow:  overflow,
+		},
+		CleanupInterval: time.Duration(c.Subscriptions.CleanupInterval),
+		StaleTimeout:    time.Duration(c.Subscriptions.StaleTimeout),
+	}
+}
+
+// HealthOptions returns the options for server.NewHealth, c must be valid. Rates
+// older than rates.max_age are reported as stale and make the service NOT_SERVING.
+func (c *Config) HealthOptions() server.HealthOptions {
+	opts := server.DefaultHealthOptions()
+	opts.MaxRateAge = time.Duration(c.Rates.MaxAge)
+	return opts
+}
+
+// RestartRequired lists the settings that differ between c and next and are
+// not applied by a reload
+func (c *Config) RestartRequired(next *Config) []string {
+	var changed []string
+	diff := func(name string, differs bool) {
+		if differs {
+			changed = append(changed, name)
+		}
+	}
+
+	diff("bind_address", c.BindAddress != next.BindAddress)
+	diff("metrics_address", c.MetricsAddress != next.MetricsAddress)
+	diff("gateway_address", c.GatewayAddress != next.GatewayAddress)
+	diff("tls", c.TLS != next.TLS)
+	diff("auth.credentials_file", c.Auth.CredentialsFile != next.Auth.CredentialsFile)
+	diff("rates.provider", c.Rates.Provider != next.Rates.Provider)
+	diff("rates.source", c.Rates.Source != next.Rates.Source)
+	diff("rates.base", c.Rates.Base != next.Rates.Base)
+	diff("rates.fallbacks", !slices.Equal(c.Rates.Fallbacks, next.Rates.Fallbacks))
+	diff("rates.provider_timeout", c.Rates.ProviderTimeout != next.Rates.ProviderTimeout)
+	diff("rates.sources", !slices.Equal(c.Rates.Sources, next.Rates.Sources))
+	diff("rates.aggregation", c.Rates.Aggregation != next.Rates.Aggregation)
+	diff("rates.pivot", c.Rates.Pivot != next.Rates.Pivot)
+	diff("rates.max_age", c.Rates.MaxAge != next.Rates.MaxAge)
+	diff("rates.max_change_percent", c.Rates.MaxChangePercent != next.Rates.MaxChangePercent)
+	diff("rates.confirm_change", c.Rates.ConfirmChange != next.Rates.ConfirmChange)
+	diff("rates.monitor_mode", c.Rates.MonitorMode != next.Rates.MonitorMode)
+	diff("rates.model", c.Rates.Model != next.Rates.Model)
+	diff("rates.drift", c.Rates.Drift != next.Rates.Drift)
+	diff("rates.annual_volatility", c.Rates.AnnualVolatility != next.Rates.AnnualVolatility)
+	diff("rates.currency_models", !maps.Equal(c.Rates.CurrencyModels, next.Rates.CurrencyModels))
+	diff("rates.seed", c.Rates.Seed != next.Rates.Seed)
+	diff("rates.tape_path", c.Rates.TapePath != next.Rates.TapePath)
+	diff("rates.replay_tape", c.Rates.ReplayTape != next.Rates.ReplayTape)
+	diff("rates.replay_speed", c.Rates.ReplaySpeed != next.Rates.ReplaySpeed)
+	diff("rates.max_backoff", c.Rates.MaxBackoff != next.Rates.MaxBackoff)
+	diff("rates.snapshot_path", c.Rates.SnapshotPath != next.Rates.SnapshotPath)
+	diff("rates.replay_size", c.Rates.ReplaySize != next.Rates.ReplaySize)
+	diff("rates.series_size", c.Rates.SeriesSize != next.Rates.SeriesSize)
+	diff("rates.series_retention", c.Rates.SeriesRetention != next.Rates.SeriesRetention)
+	diff("history", c.History != next.History)
+	diff("subscriptions", c.Subscriptions != next.Subscriptions)
+	return changed
+}
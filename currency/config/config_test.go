@@ -0,0 +1,92 @@
+package config
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("RATES_MAX_AGE", "30m")
+
+	c, err := Load("testdata/config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.BindAddress != ":9999" {
+		t.Errorf("expected bind address from the file, got %q", c.BindAddress)
+	}
+	if c.LogLevel != "warn" {
+		t.Errorf("expected the environment to override the log level, got %q", c.LogLevel)
+	}
+	if time.Duration(c.Rates.MonitorInterval) != 2*time.Second {
+		t.Errorf("expected monitor interval 2s, got %v", time.Duration(c.Rates.MonitorInterval))
+	}
+	if c.Subscriptions.QueueSize != Default().Subscriptions.QueueSize {
+		t.Errorf("expected the default queue size, got %d", c.Subscriptions.QueueSize)
+	}
+	if c.ServerOptions().Stream.Overflow != "disconnect" {
+		t.Errorf("expected overflow policy disconnect, got %q", c.ServerOptions().Stream.Overflow)
+	}
+	if c.HealthOptions().MaxRateAge != 30*time.Minute {
+		t.Errorf("expected the health check to follow rates.max_age, got %v", c.HealthOptions().MaxRateAge)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := Default()
+	c.LogLevel = "loud"
+	c.Rates.Provider = "file"
+	c.Rates.MonitorInterval = 0
+	c.Rates.MonitorMode = "replay"
+	c.Rates.Model = "brownian"
+	c.Rates.Fallbacks = []ProviderConfig{{Provider: "file"}}
+
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, setting := range []string{"log_level", "rates.source", "rates.monitor_interval", "rates.replay_tape", "rates.model", "rates.fallbacks[0].source"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("expected an error for %s, got %v", setting, err)
+		}
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	c, next := Default(), Default()
+	next.LogLevel = "error"
+	next.Rates.Volatility = 0.2
+	next.BindAddress = ":9093"
+
+	changed := c.RestartRequired(next)
+	if len(changed) != 1 || changed[0] != "bind_address" {
+		t.Errorf("expected only bind_address to require a restart, got %v", changed)
+	}
+}
+
+func TestRateSources(t *testing.T) {
+	c := Default()
+	c.Rates.Provider = "static"
+	c.Rates.Fallbacks = []ProviderConfig{{Provider: "static"}}
+	c.Rates.Sources = []SourceConfig{{ProviderConfig: ProviderConfig{Name: "backup", Provider: "static"}, Weight: 2}}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := c.RateSources(hclog.NewNullLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) != 2 || sources[0].Name != "static" || sources[1].Name != "backup" || sources[1].Weight != 2 {
+		t.Errorf("expected the static and backup sources, got %+v", sources)
+	}
+
+	c.Rates.Sources = append(c.Rates.Sources, SourceConfig{ProviderConfig: ProviderConfig{Provider: "static"}})
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "rates.sources[1].name") {
+		t.Errorf("expected a duplicate source name to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package data
+
+import "math"
+
+// suspectRate is a rate rejected by the anomaly filter together with the number
+// of fetches or sources in a row that reported it
+type suspectRate struct {
+	rate  Decimal
+	count int
+}
+
+// filterQuotes guards against a source returning bad data. A rate must be positive,
+// and must not move by more than MaxChange from the current rate of the pair,
+// otherwise it is replaced by the current rate, or dropped when the pair has none.
+// A move beyond MaxChange that is reported ConfirmChange times in a row is taken
+// as a real step change and accepted, so a pair does not stay frozen at its old
+// rate. Every rejected rate is logged.
+func (e *ExchangeRates) filterQuotes(source string, rs RateSet, current *rateGraph) RateSet {
+	base := rs.base()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	rates := make(map[string]Decimal, len(rs.Rates))
+	for code, rate := range rs.Rates {
+		pair := Pair{Base: base, Dest: code}
+		prev, known := current.edges[base][code]
+
+		reason := ""
+		change := 0.0
+		switch {
+		case rate.Sign() <= 0:
+			reason = "rate is not positive"
+		case known && e.opts.MaxChange > 0:
+			change = math.Abs(rate.Float64()/prev.rate.Float64() - 1)
+			if change <= e.opts.MaxChange {
+				break
+			}
+			if !e.confirmStep(pair, rate) {
+				reason = "rate moved more than the maximum change"
+				break
+			}
+			e.log.Info("Accepted a step change reported in a row", "source", source, "pair", base+"/"+code,
+				"rate", rate, "previous", prev.rate, "change", change, "confirmations", e.opts.ConfirmChange)
+		}
+		if reason == "" {
+			delete(e.suspects, pair)
+			rates[code] = rate
+			continue
+		}
+
+		e.anomalies.Add(1)
+		e.log.Warn("Rejected rate from source", "source", source, "pair", base+"/"+code,
+			"rate", rate, "current", prev.rate, "change", change, "max_change", e.opts.MaxChange, "reason", reason)
+		if known {
+			rates[code] = prev.rate
+		}
+	}
+
+	rs.Rates = rates
+	return rs
+}
+
+// confirmStep records rate as a suspected step change of pair and reports whether
+// it was reported ConfirmChange times in a row. A rate more than MaxChange away
+// from the suspected one starts counting again. The caller must hold the lock.
+func (e *ExchangeRates) confirmStep(pair Pair, rate Decimal) bool {
+	if e.opts.ConfirmChange <= 0 {
+		return false
+	}
+
+	s, ok := e.suspects[pair]
+	if !ok || math.Abs(rate.Float64()/s.rate.Float64()-1) > e.opts.MaxChange {
+		s = suspectRate{}
+	}
+	s.rate = rate
+	s.count++
+
+	if s.count >= e.opts.ConfirmChange {
+		delete(e.suspects, pair)
+		return true
+	}
+	if e.suspects == nil {
+		e.suspects = map[Pair]suspectRate{}
+	}
+	e.suspects[pair] = s
+	return false
+}
+
+// Anomalies returns the number of rates rejected by the anomaly filter
+func (e *ExchangeRates) Anomalies() uint64 {
+	return e.anomalies.Load()
+}
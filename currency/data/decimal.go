@@ -0,0 +1,223 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// significantDigits is the precision used when a Decimal has no finite decimal
+// expansion, for example a cross rate such as 1/3
+const significantDigits = 15
+
+// maxDecimalLength and maxDecimalExponent bound the decimals ParseDecimal accepts,
+// an exponent such as 1e1000000 would otherwise expand into a million digits.
+// Both cover every finite float64.
+const (
+	maxDecimalLength   = 400
+	maxDecimalExponent = 350
+)
+
+// decimalPattern is the only syntax ParseDecimal accepts, big.Rat.SetString also
+// parses fractions, hexadecimal, binary and underscore separated digits
+var decimalPattern = regexp.MustCompile(`^[+-]?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+
+// Decimal is an exact decimal number, the zero value is 0.
+// Values are immutable, every operation returns a new Decimal.
+type Decimal struct {
+	r *big.Rat
+}
+
+// ParseDecimal parses a decimal string such as "1.0892" or "-12.5" without loss,
+// exponents such as "1.5e3" are accepted up to a magnitude of maxDecimalExponent
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > maxDecimalLength {
+		return Decimal{}, fmt.Errorf("decimal is longer than %d characters", maxDecimalLength)
+	}
+	if !decimalPattern.MatchString(s) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("invalid decimal %q, the exponent must be within ±%d", s, maxDecimalExponent)
+		}
+	}
+
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{r: r}, nil
+}
+
+// NewDecimal returns the Decimal for the integer n
+func NewDecimal(n int64) Decimal {
+	return Decimal{r: new(big.Rat).SetInt64(n)}
+}
+
+// DecimalFromFloat converts f to a Decimal rounded to sig significant digits,
+// a sig of -1 uses the shortest representation that round trips to f.
+// NaN and Inf have no Decimal and convert to 0, callers must reject them first.
+func DecimalFromFloat(f float64, sig int) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'g', sig, 64))
+	if err != nil {
+		// FormatFloat only produces something unparsable for NaN and Inf
+		return Decimal{}
+	}
+	return d
+}
+
+func (d Decimal) rat() *big.Rat {
+	if d.r == nil {
+		return new(big.Rat)
+	}
+	return d.r
+}
+
+// Add returns d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Add(d.rat(), o.rat())}
+}
+
+// Mul returns d * o
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Mul(d.rat(), o.rat())}
+}
+
+// Quo returns d / o, it panics if o is zero
+func (d Decimal) Quo(o Decimal) Decimal {
+	return Decimal{r: new(big.Rat).Quo(d.rat(), o.rat())}
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// Cmp compares d and o and returns -1, 0 or 1
+func (d Decimal) Cmp(o Decimal) int {
+	return d.rat().Cmp(o.rat())
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String returns the exact decimal representation of d, or d rounded to 15
+// significant digits when it has no finite decimal expansion
+func (d Decimal) String() string {
+	r := d.rat()
+
+	if places, ok := finitePlaces(r.Denom()); ok {
+		return r.FloatString(places)
+	}
+
+	exp := int(math.Floor(math.Log10(math.Abs(d.Float64()))))
+	places := significantDigits - 1 - exp
+	if places < 0 {
+		places = 0
+	}
+
+	s := r.FloatString(places)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// finitePlaces reports whether 1/denom has a finite decimal expansion and
+// the number of decimal places it needs
+func finitePlaces(denom *big.Int) (int, bool) {
+	n := new(big.Int).Set(denom)
+	rem := new(big.Int)
+	two, five := big.NewInt(2), big.NewInt(5)
+
+	twos, fives := 0, 0
+	for {
+		q, r := new(big.Int).QuoRem(n, two, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		n, twos = q, twos+1
+	}
+	for {
+		q, r := new(big.Int).QuoRem(n, five, rem)
+		if r.Sign() != 0 {
+			break
+		}
+		n, fives = q, fives+1
+	}
+
+	if n.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	return max(twos, fives), true
+}
+
+// Round rounds d to the given number of decimal places using mode
+func (d Decimal) Round(places int, mode RoundingMode) (Decimal, error) {
+	if mode < RoundHalfEven || mode > RoundTruncate {
+		return Decimal{}, fmt.Errorf("unknown rounding mode %d", mode)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Rat).Mul(d.rat(), new(big.Rat).SetInt(scale))
+
+	num, den := scaled.Num(), scaled.Denom()
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+
+	if r.Sign() != 0 {
+		// compare the magnitude of the remainder with half a unit
+		half := new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(den)
+		awayFromZero := false
+
+		switch mode {
+		case RoundHalfEven:
+			awayFromZero = half > 0 || (half == 0 && q.Bit(0) == 1)
+		case RoundHalfUp:
+			awayFromZero = half >= 0
+		}
+
+		if awayFromZero {
+			q.Add(q, big.NewInt(int64(num.Sign())))
+		}
+	}
+
+	return Decimal{r: new(big.Rat).SetFrac(q, scale)}, nil
+}
+
+// StringFixed returns d formatted with exactly places decimal places,
+// d is expected to have been rounded to places already
+func (d Decimal) StringFixed(places int) string {
+	return d.rat().FloatString(places)
+}
+
+// MarshalJSON encodes d as a JSON string so no precision is lost by decoders
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts both JSON strings and JSON numbers
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(s) > 0 && s[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
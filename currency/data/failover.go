@@ -0,0 +1,73 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"time"
+)
+
+// FailoverProvider returns the rates of the first of an ordered list of providers
+// that answers within the timeout. A provider that hangs is abandoned, its call
+// finishes in the background and the result is discarded.
+type FailoverProvider struct {
+	log       hclog.Logger
+	timeout   time.Duration
+	providers []Source
+}
+
+// NewFailoverProvider creates a provider trying providers in order, a timeout of
+// 0 waits for every provider. The Base of a provider applies to its rate sets
+// that do not name a base, the Weight is not used.
+func NewFailoverProvider(l hclog.Logger, timeout time.Duration, providers ...Source) *FailoverProvider {
+	return &FailoverProvider{log: l, timeout: timeout, providers: providers}
+}
+
+func (p *FailoverProvider) Rates() (RateSet, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		rs, err := p.fetch(provider.Provider)
+		if err == nil {
+			if rs.Base == "" {
+				rs.Base = provider.Base
+			}
+			if i > 0 {
+				p.log.Warn("Rates fetched from a fallback provider", "provider", provider.Name)
+			}
+			return rs, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+		if i < len(p.providers)-1 {
+			p.log.Warn("Provider failed, failing over", "provider", provider.Name, "next", p.providers[i+1].Name, "error", err)
+		}
+	}
+	return RateSet{}, errors.Join(errs...)
+}
+
+// fetch calls provider and gives up after the timeout
+func (p *FailoverProvider) fetch(provider RateProvider) (RateSet, error) {
+	if p.timeout <= 0 {
+		return provider.Rates()
+	}
+
+	type result struct {
+		rs  RateSet
+		err error
+	}
+	ch := make(chan result, 1) // buffered so an abandoned call can still return
+	go func() {
+		rs, err := provider.Rates()
+		ch <- result{rs, err}
+	}()
+
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-ch:
+		return r.rs, r.err
+	case <-timer.C:
+		return RateSet{}, fmt.Errorf("no rates within %s", p.timeout)
+	}
+}
@@ -0,0 +1,138 @@
+package data
+
+import (
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingProvider blocks until release is closed
+type hangingProvider struct {
+	release chan struct{}
+}
+
+func (p *hangingProvider) Rates() (RateSet, error) {
+	<-p.release
+	return RateSet{}, errors.New("released")
+}
+
+func TestFailoverProvider(t *testing.T) {
+	hanging := &hangingProvider{release: make(chan struct{})}
+	defer close(hanging.release)
+
+	p := NewFailoverProvider(hclog.NewNullLogger(), 20*time.Millisecond,
+		Source{Name: "hanging", Provider: hanging},
+		Source{Name: "down", Provider: &flakyProvider{}},
+		Source{Name: "usd", Provider: NewStaticProvider(map[string]string{"EUR": "0.9"}), Base: "USD"},
+	)
+	rs, err := p.Rates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Base != "USD" || rs.Rates["EUR"].String() != "0.9" {
+		t.Errorf("expected the USD rates of the last provider, got %+v", rs)
+	}
+
+	p = NewFailoverProvider(hclog.NewNullLogger(), 20*time.Millisecond,
+		Source{Name: "hanging", Provider: hanging},
+		Source{Name: "down", Provider: &flakyProvider{}},
+	)
+	_, err = p.Rates()
+	if err == nil || !strings.Contains(err.Error(), "hanging") || !strings.Contains(err.Error(), "down") {
+		t.Errorf("expected the errors of both providers, got %v", err)
+	}
+}
+
+func TestAnomalousRatesAreRejected(t *testing.T) {
+	provider := &sequenceProvider{sets: []map[string]string{
+		{"USD": "1.10", "GBP": "0.85"},
+		{"USD": "1100", "GBP": "0.86", "CHF": "0"},
+		{"USD": "1.15", "GBP": "0.86"},
+	}}
+	rates, err := NewRates(hclog.NewNullLogger(), provider, DefaultRatesOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rates.fetchRates(); err != nil {
+		t.Fatal(err)
+	}
+	if usd, _ := rates.GetRate("EUR", "USD"); usd.String() != "1.1" {
+		t.Errorf("expected the 1000x jump of USD to be rejected, got %s", usd)
+	}
+	if gbp, _ := rates.GetRate("EUR", "GBP"); gbp.String() != "0.86" {
+		t.Errorf("expected the GBP update to be applied, got %s", gbp)
+	}
+	if rates.HasCurrency("CHF") {
+		t.Error("expected the zero CHF rate to be rejected")
+	}
+	if n := rates.Anomalies(); n != 2 {
+		t.Errorf("expected 2 rejected rates, got %d", n)
+	}
+
+	if _, err := rates.fetchRates(); err != nil {
+		t.Fatal(err)
+	}
+	if usd, _ := rates.GetRate("EUR", "USD"); usd.String() != "1.15" {
+		t.Errorf("expected a move within the maximum change to be applied, got %s", usd)
+	}
+}
+
+func TestPersistentStepChangeIsAccepted(t *testing.T) {
+	provider := &sequenceProvider{sets: []map[string]string{
+		{"USD": "1.10"},
+		{"USD": "2.00"},
+		{"USD": "1.11"},
+		{"USD": "2.00"},
+		{"USD": "2.02"},
+	}}
+	rates, err := NewRates(hclog.NewNullLogger(), provider, DefaultRatesOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the jump is rejected until it was reported ConfirmChange times in a row,
+	// the rate in between starts counting again
+	for i, expected := range []string{"1.1", "1.11", "1.11", "1.11", "2.02", "2.02"} {
+		if _, err := rates.fetchRates(); err != nil {
+			t.Fatal(err)
+		}
+		if usd, _ := rates.GetRate("EUR", "USD"); usd.String() != expected {
+			t.Errorf("fetch %d: expected %s, got %s", i+2, expected, usd)
+		}
+	}
+	if n := rates.Anomalies(); n != 3 {
+		t.Errorf("expected 3 rejected rates, got %d", n)
+	}
+}
+
+func TestStaleAfterMaxAge(t *testing.T) {
+	opts := DefaultRatesOptions()
+	opts.Monitor.Mode = MonitorLive
+	rates, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates.Stale() {
+		t.Fatal("expected freshly fetched rates not to be stale")
+	}
+
+	rates.mutex.Lock()
+	rates.updatedAt = time.Now().Add(-opts.MaxAge - time.Minute)
+	rates.mutex.Unlock()
+
+	if !rates.Stale() {
+		t.Error("expected rates older than the maximum age to be stale")
+	}
+	if quote, err := rates.GetQuote("EUR", "USD"); err != nil || !quote.Stale {
+		t.Errorf("expected the quote to be marked stale, got %+v, %v", quote, err)
+	}
+
+	// simulated rates do not age
+	rates.opts.Monitor.Mode = MonitorSimulate
+	if rates.Stale() {
+		t.Error("expected simulated rates not to be stale")
+	}
+}
@@ -0,0 +1,276 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Aggregation selects how the quotes of several sources for the same pair are combined
+type Aggregation string
+
+const (
+	// AggregateMedian takes the median of the quotes, the mean of the two middle
+	// quotes for an even number of sources
+	AggregateMedian Aggregation = "median"
+	// AggregateWeighted takes the mean of the quotes weighted by the source weights
+	AggregateWeighted Aggregation = "weighted"
+)
+
+// ParseAggregation converts a configuration value into an Aggregation
+func ParseAggregation(s string) (Aggregation, error) {
+	switch a := Aggregation(strings.ToLower(s)); a {
+	case AggregateMedian, AggregateWeighted:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown aggregation %q", s)
+	}
+}
+
+// Source is a named rate provider, the rates of all sources are combined into a
+// graph of quoted pairs
+type Source struct {
+	Name     string
+	Provider RateProvider
+	// Base is the currency the rates are quoted against when the RateSet of the
+	// provider does not say, EUR when empty
+	Base string
+	// Weight is the weight of the quotes of the source in weighted aggregation,
+	// 0 counts as 1
+	Weight float64
+}
+
+// Route describes how a rate was computed
+type Route struct {
+	// Path lists the currencies the rate was converted through, from base to dest
+	Path []string
+	// Sources are the names of the sources quoting the pairs along Path
+	Sources []string
+}
+
+// sourceRates is a rate set fetched from a source
+type sourceRates struct {
+	name   string
+	weight float64
+	rates  RateSet
+}
+
+// sourceQuote is the rate of a pair quoted by a single source
+type sourceQuote struct {
+	source string
+	weight float64
+	rate   Decimal
+}
+
+// graphEdge is the aggregated rate of a quoted pair
+type graphEdge struct {
+	rate    Decimal
+	sources []string // sorted names of the sources quoting the pair
+}
+
+// rateGraph holds the quoted pairs of currencies, every pair is stored in both
+// directions. A graph is never modified once built, snapshots share it.
+type rateGraph struct {
+	edges map[string]map[string]graphEdge
+}
+
+// buildGraph aggregates the quotes of all sets into a graph, a set quotes each of
+// its currencies against its base
+func buildGraph(sets []sourceRates, agg Aggregation) *rateGraph {
+	type pair struct{ from, to string }
+
+	// quotes are collected in the direction of the lower currency code
+	quotes := map[pair][]sourceQuote{}
+	for _, set := range sets {
+		base := set.rates.base()
+		for code, rate := range set.rates.Rates {
+			if code == base || rate.Sign() <= 0 {
+				continue
+			}
+			p := pair{base, code}
+			if code < base {
+				p, rate = pair{code, base}, NewDecimal(1).Quo(rate)
+			}
+			quotes[p] = append(quotes[p], sourceQuote{source: set.name, weight: set.weight, rate: rate})
+		}
+	}
+
+	g := &rateGraph{edges: map[string]map[string]graphEdge{}}
+	for p, qs := range quotes {
+		sources := make([]string, len(qs))
+		for i, q := range qs {
+			sources[i] = q.source
+		}
+		sort.Strings(sources)
+
+		rate := aggregate(qs, agg)
+		g.add(p.from, p.to, graphEdge{rate: rate, sources: sources})
+		g.add(p.to, p.from, graphEdge{rate: NewDecimal(1).Quo(rate), sources: sources})
+	}
+	return g
+}
+
+// singleSourceGraph returns the graph of a single rate set
+func singleSourceGraph(name string, rs RateSet) *rateGraph {
+	return buildGraph([]sourceRates{{name: name, rates: rs}}, AggregateMedian)
+}
+
+func (g *rateGraph) add(from, to string, e graphEdge) {
+	if g.edges[from] == nil {
+		g.edges[from] = map[string]graphEdge{}
+	}
+	g.edges[from][to] = e
+}
+
+// aggregate combines the quotes of several sources for the same pair
+func aggregate(quotes []sourceQuote, agg Aggregation) Decimal {
+	if len(quotes) == 1 {
+		return quotes[0].rate
+	}
+
+	if agg == AggregateWeighted {
+		sum, total := NewDecimal(0), NewDecimal(0)
+		for _, q := range quotes {
+			w := NewDecimal(1)
+			if q.weight > 0 {
+				w = DecimalFromFloat(q.weight, -1)
+			}
+			sum = sum.Add(q.rate.Mul(w))
+			total = total.Add(w)
+		}
+		return sum.Quo(total)
+	}
+
+	rates := make([]Decimal, len(quotes))
+	for i, q := range quotes {
+		rates[i] = q.rate
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Cmp(rates[j]) < 0 })
+	mid := len(rates) / 2
+	if len(rates)%2 == 1 {
+		return rates[mid]
+	}
+	return rates[mid-1].Add(rates[mid]).Quo(NewDecimal(2))
+}
+
+// hop is the best way found to reach a currency from the start of routes
+type hop struct {
+	prev    string
+	depth   int
+	support int // smallest number of sources of a pair on the path
+}
+
+// routes finds the best path from base to every reachable currency. The best path
+// has the fewest conversions, and of those the most sources on its weakest pair.
+// Remaining ties go to the path through the lower currency codes.
+func (g *rateGraph) routes(base string) map[string]hop {
+	best := map[string]hop{base: {support: math.MaxInt}}
+	for layer := []string{base}; len(layer) > 0; {
+		var next []string
+		for _, from := range layer {
+			for _, to := range sortedEdges(g.edges[from]) {
+				support := min(best[from].support, len(g.edges[from][to].sources))
+				h, seen := best[to]
+				switch {
+				case !seen:
+					best[to] = hop{prev: from, depth: best[from].depth + 1, support: support}
+					next = append(next, to)
+				case h.depth == best[from].depth+1 && support > h.support:
+					best[to] = hop{prev: from, depth: h.depth, support: support}
+				}
+			}
+		}
+		sort.Strings(next)
+		layer = next
+	}
+	return best
+}
+
+// sortedEdges returns the currencies of edges in order, so routes picks the same
+// path on every call
+func sortedEdges(edges map[string]graphEdge) []string {
+	codes := make([]string, 0, len(edges))
+	for code := range edges {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// follow converts along the best path to dest found by routes
+func (g *rateGraph) follow(best map[string]hop, dest string) (Decimal, Route) {
+	path := []string{dest}
+	for code := dest; best[code].depth > 0; code = best[code].prev {
+		path = append(path, best[code].prev)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	rate := NewDecimal(1)
+	seen := map[string]bool{}
+	var sources []string
+	for i := 1; i < len(path); i++ {
+		e := g.edges[path[i-1]][path[i]]
+		rate = rate.Mul(e.rate)
+		for _, s := range e.sources {
+			if !seen[s] {
+				seen[s] = true
+				sources = append(sources, s)
+			}
+		}
+	}
+	sort.Strings(sources)
+	return rate, Route{Path: path, Sources: sources}
+}
+
+// rate returns the rate between base and dest through the best path
+func (g *rateGraph) rate(base, dest string) (Decimal, Route, error) {
+	if _, ok := g.edges[base]; !ok {
+		return Decimal{}, Route{}, fmt.Errorf("rate not found for currency %s", base)
+	}
+	if _, ok := g.edges[dest]; !ok {
+		return Decimal{}, Route{}, fmt.Errorf("rate not found for currency %s", dest)
+	}
+
+	best := g.routes(base)
+	if _, ok := best[dest]; !ok {
+		return Decimal{}, Route{}, fmt.Errorf("no quoted path from %s to %s", base, dest)
+	}
+	rate, route := g.follow(best, dest)
+	return rate, route, nil
+}
+
+// pivotRates returns the rate of every currency reachable from pivot against
+// pivot, the pivot itself has rate 1
+func (g *rateGraph) pivotRates(pivot string) map[string]Decimal {
+	best := g.routes(pivot)
+	rates := make(map[string]Decimal, len(best))
+	for code := range best {
+		rates[code], _ = g.follow(best, code)
+	}
+	return rates
+}
+
+// scale returns a copy of the graph after every currency moved against the others
+// by its factor, a pair from → to is multiplied by factors[to] / factors[from].
+// Currencies without a factor keep their rates.
+func (g *rateGraph) scale(factors map[string]Decimal) *rateGraph {
+	factor := func(code string) Decimal {
+		if f, ok := factors[code]; ok {
+			return f
+		}
+		return NewDecimal(1)
+	}
+
+	scaled := &rateGraph{edges: make(map[string]map[string]graphEdge, len(g.edges))}
+	for from, edges := range g.edges {
+		for to, e := range edges {
+			rate := e.rate.Mul(factor(to)).Quo(factor(from))
+			// rounding keeps the rationals from growing on every tick
+			scaled.add(from, to, graphEdge{rate: DecimalFromFloat(rate.Float64(), significantDigits), sources: e.sources})
+		}
+	}
+	return scaled
+}
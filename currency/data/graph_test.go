@@ -0,0 +1,120 @@
+package data
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"reflect"
+	"testing"
+)
+
+// quotes returns the rate set of a source quoting rates against base
+func quotes(name, base string, rates map[string]string) sourceRates {
+	set := map[string]Decimal{}
+	for code, rate := range rates {
+		set[code], _ = ParseDecimal(rate)
+	}
+	return sourceRates{name: name, rates: RateSet{Base: base, Rates: set}}
+}
+
+func TestGraphRates(t *testing.T) {
+	sets := []sourceRates{
+		quotes("a", "", map[string]string{"USD": "1.1", "GBP": "0.8"}),
+		quotes("b", "EUR", map[string]string{"USD": "1.3"}),
+		quotes("c", "USD", map[string]string{"JPY": "150"}),
+		quotes("d", "CHF", map[string]string{"XAU": "2"}),
+	}
+	sets[0].weight = 3
+	g := buildGraph(sets, AggregateMedian)
+
+	tests := []struct {
+		base, dest string
+		rate       string
+		route      Route
+	}{
+		{"EUR", "USD", "1.2", Route{Path: []string{"EUR", "USD"}, Sources: []string{"a", "b"}}},
+		{"USD", "EUR", "0.833333333333333", Route{Path: []string{"USD", "EUR"}, Sources: []string{"a", "b"}}},
+		{"USD", "JPY", "150", Route{Path: []string{"USD", "JPY"}, Sources: []string{"c"}}},
+		{"EUR", "JPY", "180", Route{Path: []string{"EUR", "USD", "JPY"}, Sources: []string{"a", "b", "c"}}},
+		{"GBP", "JPY", "225", Route{Path: []string{"GBP", "EUR", "USD", "JPY"}, Sources: []string{"a", "b", "c"}}},
+	}
+	for _, tt := range tests {
+		rate, route, err := g.rate(tt.base, tt.dest)
+		if err != nil {
+			t.Errorf("%s/%s: %v", tt.base, tt.dest, err)
+			continue
+		}
+		if rate.String() != tt.rate {
+			t.Errorf("%s/%s: expected %s, got %s", tt.base, tt.dest, tt.rate, rate)
+		}
+		if !reflect.DeepEqual(route, tt.route) {
+			t.Errorf("%s/%s: expected route %+v, got %+v", tt.base, tt.dest, tt.route, route)
+		}
+	}
+
+	if _, _, err := g.rate("EUR", "XAU"); err == nil {
+		t.Error("expected an error without a path between EUR and XAU")
+	}
+	if _, _, err := g.rate("EUR", "ABC"); err == nil {
+		t.Error("expected an error for an unknown currency")
+	}
+
+	weighted := buildGraph(sets, AggregateWeighted)
+	if rate, _, _ := weighted.rate("EUR", "USD"); rate.String() != "1.15" {
+		t.Errorf("expected a weighted EUR/USD of 1.15, got %s", rate)
+	}
+
+	// the pivot rates only contain the currencies connected to the pivot
+	pivot := g.pivotRates("USD")
+	if len(pivot) != 4 || pivot["USD"].String() != "1" || pivot["JPY"].String() != "150" {
+		t.Errorf("expected EUR, GBP, JPY and USD against USD, got %v", pivot)
+	}
+}
+
+func TestGraphPrefersCorroboratedPaths(t *testing.T) {
+	g := buildGraph([]sourceRates{
+		quotes("a", "EUR", map[string]string{"GBP": "1", "USD": "1"}),
+		quotes("b", "EUR", map[string]string{"USD": "1"}),
+		quotes("c", "GBP", map[string]string{"CHF": "2"}),
+		quotes("d", "USD", map[string]string{"CHF": "4"}),
+		quotes("e", "USD", map[string]string{"CHF": "4"}),
+	}, AggregateMedian)
+
+	// both paths take two conversions, the one through USD is quoted by two sources
+	rate, route, err := g.rate("EUR", "CHF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate.String() != "4" || !reflect.DeepEqual(route.Path, []string{"EUR", "USD", "CHF"}) {
+		t.Errorf("expected 4 through USD, got %s through %v", rate, route.Path)
+	}
+}
+
+func TestRatesFromSources(t *testing.T) {
+	sources := []Source{
+		{Name: "down", Provider: &flakyProvider{}},
+		{Name: "usd", Provider: NewStaticProvider(map[string]string{"EUR": "0.8", "JPY": "150"}), Base: "USD"},
+		{Name: "eur", Provider: NewStaticProvider(map[string]string{"USD": "1.3"})},
+	}
+	rates, err := NewRatesFromSources(hclog.NewNullLogger(), sources, DefaultRatesOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := rates.FetchStats(); stats.Failures != 0 {
+		t.Errorf("expected a single failing source not to fail the fetch, got %+v", stats)
+	}
+
+	quote, err := rates.GetQuote("EUR", "JPY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// median of 1.25 and 1.3 for EUR/USD
+	if quote.Rate.String() != "191.25" || !reflect.DeepEqual(quote.Route.Sources, []string{"eur", "usd"}) {
+		t.Errorf("expected 191.25 from eur and usd, got %s from %v", quote.Rate, quote.Route.Sources)
+	}
+	if !rates.HasCurrency("JPY") {
+		t.Error("expected JPY to be available through USD")
+	}
+
+	if _, err := NewRatesFromSources(hclog.NewNullLogger(), sources[:1], DefaultRatesOptions()); err == nil {
+		t.Error("expected an error when every source fails")
+	}
+}
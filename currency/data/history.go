@@ -0,0 +1,231 @@
+package data
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ECB history feeds, both use the same document layout with one Cube per day
+const (
+	ECBHistory90DayURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	ECBHistoryFullURL  = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+)
+
+// DateLayout is the format used for dates in the ECB feeds and the history API
+const DateLayout = "2006-01-02"
+
+// ErrNoHistory is returned when no rates are available for the requested date
+var ErrNoHistory = errors.New("no historical rates available")
+
+// ErrAfterHistory is returned for dates after the last publication plus MaxPublicationGap,
+// the rate in effect on such a date is not known yet
+var ErrAfterHistory = errors.New("date is after the last published rates")
+
+// MaxPublicationGap is the longest time the ECB goes without publishing, a
+// weekend next to the Easter holidays, the last publication is used up to then
+const MaxPublicationGap = 5 * 24 * time.Hour
+
+// HistoryCubes is the ECB history document, rates grouped by publication day
+type HistoryCubes struct {
+	Days []DayCube `xml:"Cube>Cube"`
+}
+
+// DayCube holds the rates published by the ECB on a single day
+type DayCube struct {
+	Time  string `xml:"time,attr"`
+	Rates []Cube `xml:"Cube"`
+}
+
+// DatedRate is an exchange rate published on a given day
+type DatedRate struct {
+	Date time.Time
+	Rate Decimal
+}
+
+// HistoricalRates holds EUR reference rates indexed by publication date
+type HistoricalRates struct {
+	log   hclog.Logger
+	days  map[string]map[string]Decimal // date -> currency -> rate
+	dates []string                      // sorted publication dates
+	mutex sync.RWMutex
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewHistoricalRates creates an empty HistoricalRates store
+func NewHistoricalRates(logger hclog.Logger) *HistoricalRates {
+	return &HistoricalRates{
+		log:     logger,
+		days:    map[string]map[string]Decimal{},
+		closeCh: make(chan struct{}),
+	}
+}
+
+// RefreshEvery loads source again every interval in the background so new
+// publications are picked up, failures are logged and retried on the next tick
+func (h *HistoricalRates) RefreshEvery(source string, interval time.Duration) {
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := h.LoadSource(source); err != nil {
+					h.log.Error("Unable to refresh historical rates", "source", source, "error", err)
+				}
+			case <-h.closeCh:
+				return
+			}
+		}
+	}()
+}
+
+// Close stops the background refresh
+func (h *HistoricalRates) Close() {
+	h.once.Do(func() {
+		close(h.closeCh)
+		h.wg.Wait()
+	})
+}
+
+// LoadSource loads history from source, which is either an http(s) URL
+// for one of the ECB history feeds or the path of a local file in the same format
+func (h *HistoricalRates) LoadSource(source string) error {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 60 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return fmt.Errorf("unable to fetch history: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("expected success code 200, got %d", resp.StatusCode)
+		}
+		return h.Load(resp.Body)
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("unable to open history file: %w", err)
+	}
+	defer f.Close()
+
+	return h.Load(f)
+}
+
+// Load decodes an ECB history document and merges it into the store
+func (h *HistoricalRates) Load(r io.Reader) error {
+	parsed := &HistoryCubes{}
+	if err := xml.NewDecoder(r).Decode(parsed); err != nil {
+		return fmt.Errorf("unable to decode XML: %w", err)
+	}
+
+	days := make(map[string]map[string]Decimal, len(parsed.Days))
+	for _, day := range parsed.Days {
+		if _, err := time.Parse(DateLayout, day.Time); err != nil {
+			return fmt.Errorf("invalid date %q: %w", day.Time, err)
+		}
+
+		rates, err := parseCubes(day.Rates)
+		if err != nil {
+			return fmt.Errorf("invalid rates for %s: %w", day.Time, err)
+		}
+		rates["EUR"] = NewDecimal(1)
+		days[day.Time] = rates
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for date, rates := range days {
+		if _, exists := h.days[date]; !exists {
+			h.dates = append(h.dates, date)
+		}
+		h.days[date] = rates
+	}
+	sort.Strings(h.dates)
+
+	h.log.Info("Loaded historical rates", "days", len(days), "total_days", len(h.dates))
+	return nil
+}
+
+// GetRate returns the rate between base and dest that was in effect on date.
+// The ECB does not publish on weekends and holidays, so the most recent
+// publication on or before date is used; its date is returned with the rate.
+// Dates more than MaxPublicationGap after the last publication are rejected.
+func (h *HistoricalRates) GetRate(base, dest string, date time.Time) (DatedRate, error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	key := date.Format(DateLayout)
+	if n := len(h.dates); n > 0 {
+		last, _ := time.Parse(DateLayout, h.dates[n-1])
+		if date.Sub(last) > MaxPublicationGap {
+			return DatedRate{}, fmt.Errorf("%w on %s", ErrAfterHistory, h.dates[n-1])
+		}
+	}
+	// index of the first date after key, the one before it is in effect
+	i := sort.Search(len(h.dates), func(i int) bool { return h.dates[i] > key })
+	if i == 0 {
+		return DatedRate{}, fmt.Errorf("%w on or before %s", ErrNoHistory, key)
+	}
+
+	effective := h.dates[i-1]
+	rate, err := crossRate(h.days[effective], base, dest)
+	if err != nil {
+		return DatedRate{}, fmt.Errorf("%s: %w", effective, err)
+	}
+
+	d, _ := time.Parse(DateLayout, effective)
+	return DatedRate{Date: d, Rate: rate}, nil
+}
+
+// GetSeries returns the rates between base and dest for every publication
+// day between from and to inclusive, ordered by date. Currencies are added to
+// and dropped from the feed over time, days on which the pair cannot be priced
+// are skipped.
+func (h *HistoricalRates) GetSeries(base, dest string, from, to time.Time) ([]DatedRate, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("end date %s is before start date %s", to.Format(DateLayout), from.Format(DateLayout))
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	fromKey, toKey := from.Format(DateLayout), to.Format(DateLayout)
+	start := sort.SearchStrings(h.dates, fromKey)
+
+	var series []DatedRate
+	for _, date := range h.dates[start:] {
+		if date > toKey {
+			break
+		}
+
+		rate, err := crossRate(h.days[date], base, dest)
+		if err != nil {
+			continue
+		}
+
+		d, _ := time.Parse(DateLayout, date)
+		series = append(series, DatedRate{Date: d, Rate: rate})
+	}
+
+	if len(series) == 0 {
+		return nil, fmt.Errorf("%w between %s and %s", ErrNoHistory, fromKey, toKey)
+	}
+	return series, nil
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"testing"
+	"time"
+)
+
+func TestHistoricalRates(t *testing.T) {
+	h := NewHistoricalRates(hclog.Default())
+	if err := h.LoadSource("testdata/eurofxref-hist.xml"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sunday falls back to the previous Friday's publication
+	sunday, _ := time.Parse(DateLayout, "2024-10-13")
+	dr, err := h.GetRate("EUR", "USD", sunday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dr.Date.Format(DateLayout) != "2024-10-11" || dr.Rate.String() != "1.0946" {
+		t.Errorf("expected 1.0946 on 2024-10-11, got %v on %s", dr.Rate, dr.Date.Format(DateLayout))
+	}
+
+	before, _ := time.Parse(DateLayout, "2024-10-10")
+	if _, err := h.GetRate("EUR", "USD", before); err == nil {
+		t.Error("expected an error for a date before the history")
+	}
+
+	to, _ := time.Parse(DateLayout, "2024-10-15")
+	series, err := h.GetSeries("EUR", "GBP", sunday, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Rate.String() != "0.8351" || series[1].Rate.String() != "0.8345" {
+		t.Errorf("unexpected series %v", series)
+	}
+}
+
+func TestHistoricalRatesAfterLastPublication(t *testing.T) {
+	h := NewHistoricalRates(hclog.Default())
+	if err := h.LoadSource("testdata/eurofxref-hist.xml"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the last publication stays in effect over a long weekend
+	saturday, _ := time.Parse(DateLayout, "2024-10-19")
+	if dr, err := h.GetRate("EUR", "USD", saturday); err != nil || dr.Date.Format(DateLayout) != "2024-10-15" {
+		t.Errorf("expected the rate of 2024-10-15, got %v %v", dr, err)
+	}
+
+	later, _ := time.Parse(DateLayout, "2024-10-25")
+	if _, err := h.GetRate("EUR", "USD", later); !errors.Is(err, ErrAfterHistory) {
+		t.Errorf("expected ErrAfterHistory, got %v", err)
+	}
+}
+
+func TestHistoricalSeriesWithGaps(t *testing.T) {
+	h := NewHistoricalRates(hclog.Default())
+	if err := h.LoadSource("testdata/eurofxref-hist-gap.xml"); err != nil {
+		t.Fatal(err)
+	}
+
+	from, _ := time.Parse(DateLayout, "2022-02-28")
+	to, _ := time.Parse(DateLayout, "2022-03-03")
+
+	// RUB is only published on two of the four days
+	series, err := h.GetSeries("USD", "RUB", from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Date.Format(DateLayout) != "2022-03-01" || series[1].Date.Format(DateLayout) != "2022-03-02" {
+		t.Errorf("expected the two days quoting RUB, got %v", series)
+	}
+
+	if _, err := h.GetSeries("EUR", "RUB", to, to); !errors.Is(err, ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory when no day quotes the pair, got %v", err)
+	}
+}
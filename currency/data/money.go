@@ -0,0 +1,29 @@
+package data
+
+// RoundingMode selects how converted amounts are rounded to minor units
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest minor unit, ties to the even neighbour
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest minor unit, ties away from zero
+	RoundHalfUp
+	// RoundTruncate drops any fraction of a minor unit
+	RoundTruncate
+)
+
+// minorUnits lists the ISO 4217 currencies which do not use two decimal places
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits returns the number of decimal places ISO 4217 defines for currency
+func MinorUnits(currency string) int {
+	if mu, ok := minorUnits[currency]; ok {
+		return mu
+	}
+	return 2
+}
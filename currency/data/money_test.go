@@ -0,0 +1,97 @@
+package data
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		mode     RoundingMode
+		expected string
+	}{
+		{"12.345", "EUR", RoundHalfEven, "12.34"},
+		{"12.355", "EUR", RoundHalfEven, "12.36"},
+		{"12.345", "EUR", RoundHalfUp, "12.35"},
+		{"12.349", "EUR", RoundTruncate, "12.34"},
+		{"2.675", "EUR", RoundHalfUp, "2.68"},
+		{"1234.5", "JPY", RoundHalfEven, "1234"},
+		{"1234.5", "JPY", RoundHalfUp, "1235"},
+		{"1.2345", "KWD", RoundHalfUp, "1.235"},
+		{"-12.345", "EUR", RoundHalfUp, "-12.35"},
+		{"-12.345", "EUR", RoundHalfEven, "-12.34"},
+		{"-12.349", "EUR", RoundTruncate, "-12.34"},
+	}
+
+	for _, tc := range tests {
+		amount, err := ParseDecimal(tc.amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		places := MinorUnits(tc.currency)
+		got, err := amount.Round(places, tc.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.StringFixed(places) != tc.expected {
+			t.Errorf("Round(%s, %s, %d) = %s, expected %s", tc.amount, tc.currency, tc.mode, got.StringFixed(places), tc.expected)
+		}
+	}
+}
+
+func TestDecimalString(t *testing.T) {
+	usd, _ := ParseDecimal("1.0892")
+	gbp, _ := ParseDecimal("0.8345")
+
+	if usd.String() != "1.0892" {
+		t.Errorf("expected 1.0892, got %s", usd.String())
+	}
+
+	// a cross rate has no finite expansion and is rounded to 15 significant digits
+	if cross := gbp.Quo(usd).String(); cross != "0.766158648549394" {
+		t.Errorf("expected 0.766158648549394, got %s", cross)
+	}
+
+	// 0.1 + 0.2 style float errors do not occur
+	if product := usd.Mul(NewDecimal(3)).String(); product != "3.2676" {
+		t.Errorf("expected 3.2676, got %s", product)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+		valid    bool
+	}{
+		{"12.5", "12.5", true},
+		{"-0.001", "-0.001", true},
+		{"1.5e3", "1500", true},
+		{"2E-2", "0.02", true},
+		{"1e1000000", "", false},
+		{"1e-1000000", "", false},
+		{"1e", "", false},
+		{"1/3", "", false},
+		{"1p3000000", "", false},
+		{"0x1p-3", "", false},
+		{"0b101", "", false},
+		{"1_000", "", false},
+		{".5", "", false},
+		{"", "", false},
+		{"1" + strings.Repeat("0", maxDecimalLength), "", false},
+	}
+
+	for _, tc := range tests {
+		d, err := ParseDecimal(tc.s)
+		if (err == nil) != tc.valid {
+			t.Errorf("ParseDecimal(%.20q): expected valid %v, got error %v", tc.s, tc.valid, err)
+			continue
+		}
+		if tc.valid && d.String() != tc.expected {
+			t.Errorf("ParseDecimal(%q) = %s, expected %s", tc.s, d, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package data
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// simulatedDigits is the number of significant digits kept for simulated rates,
+// it matches the precision of the ECB reference rates
+const simulatedDigits = 6
+
+// MonitorMode selects how MonitorRates updates the rates
+type MonitorMode string
+
+const (
+	// MonitorSimulate applies a random walk to the loaded rates on every tick
+	MonitorSimulate MonitorMode = "simulate"
+	// MonitorLive re-fetches the rates from the provider on every tick
+	MonitorLive MonitorMode = "live"
+	// MonitorReplay publishes the rates recorded on a tape
+	MonitorReplay MonitorMode = "replay"
+)
+
+// ParseMonitorMode converts a configuration value into a MonitorMode
+func ParseMonitorMode(s string) (MonitorMode, error) {
+	switch m := MonitorMode(strings.ToLower(s)); m {
+	case MonitorSimulate, MonitorLive, MonitorReplay:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown monitor mode %q", s)
+	}
+}
+
+// MonitorOptions configures MonitorRates
+type MonitorOptions struct {
+	Mode MonitorMode
+	// Interval is the time between two updates
+	Interval time.Duration
+
+	// Model moves the rates in simulate mode, nil is a UniformWalk of Volatility
+	Model SimulationModel
+	// Volatility is the maximum relative change applied to a rate per tick by the
+	// default model
+	Volatility float64
+	// Seed seeds the model in simulate mode, 0 uses a time based seed
+	Seed int64
+
+	// Tape is the file of rates published in replay mode
+	Tape string
+	// Speed divides the delays between the frames of the tape, 0 replays them at
+	// the original speed
+	Speed float64
+
+	// MaxBackoff caps the delay between retries when the provider fails in live mode
+	MaxBackoff time.Duration
+}
+
+// DefaultMonitorOptions returns the original demo behaviour, a random walk of up to 10% per tick
+func DefaultMonitorOptions() MonitorOptions {
+	return MonitorOptions{
+		Mode:       MonitorSimulate,
+		Interval:   5 * time.Second,
+		Volatility: 0.1,
+		MaxBackoff: 5 * time.Minute,
+	}
+}
+
+// Mode returns the monitor mode the rates were created with
+func (e *ExchangeRates) Mode() MonitorMode {
+	return e.opts.Monitor.Mode
+}
+
+// Reconfigure applies the Interval and Volatility of opts to a running monitor,
+// the other options only take effect when the service is restarted
+func (e *ExchangeRates) Reconfigure(opts MonitorOptions) {
+	e.mutex.Lock()
+	e.opts.Monitor.Volatility = opts.Volatility
+	changed := opts.Interval > 0 && opts.Interval != e.opts.Monitor.Interval
+	if changed {
+		e.opts.Monitor.Interval = opts.Interval
+	}
+	e.mutex.Unlock()
+
+	if !changed {
+		return
+	}
+
+	// replace an interval the monitor has not picked up yet
+	select {
+	case <-e.intervalCh:
+	default:
+	}
+	e.intervalCh <- opts.Interval
+}
+
+// simulateRates moves every rate with the simulation model on each tick, the
+// model is passed the interval rather than the elapsed time so a seeded run
+// produces the same rates regardless of the load of the machine
+func (e *ExchangeRates) simulateRates(interval time.Duration, ret chan struct{}) {
+	seed := e.opts.Monitor.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rnd := rand.New(rand.NewSource(seed))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case interval = <-e.intervalCh:
+			ticker.Reset(interval)
+		case <-ticker.C:
+			e.mutex.Lock()
+			model := e.opts.Monitor.Model
+			if model == nil {
+				model = UniformWalk{Volatility: e.opts.Monitor.Volatility}
+			}
+			factors := make(map[string]Decimal, len(e.rates))
+			for _, k := range sortedCodes(e.rates) {
+				if k == e.opts.Pivot {
+					// the pivot keeps rate 1
+					continue
+				}
+				// Modify the rate, keeping the precision of the published rates
+				v := e.rates[k]
+				e.rates[k] = DecimalFromFloat(model.Next(rnd, k, v.Float64(), interval), simulatedDigits)
+				if v.Sign() != 0 {
+					factors[k] = e.rates[k].Quo(v)
+				}
+			}
+			// move the quoted pairs along, cross rates follow the simulated rates
+			e.graph = e.graph.scale(factors)
+			// simulated rates are a new snapshot produced now, updatedAt keeps the
+			// time of the provider data they are derived from
+			e.asOf = time.Now()
+			e.publish()
+			e.mutex.Unlock()
+
+			// Notify updates
+			if !e.notify(ret) {
+				e.log.Info("MonitorRates received shutdown signal")
+				return
+			}
+		case <-e.changedCh:
+			if !e.notify(ret) {
+				e.log.Info("MonitorRates received shutdown signal")
+				return
+			}
+		case <-e.closeCh:
+			e.log.Info("MonitorRates received shutdown signal")
+			return
+		}
+	}
+}
+
+// sortedCodes returns the currency codes of rates in order, map iteration is
+// random and would hand the random numbers to different currencies on every run
+func sortedCodes(rates map[string]Decimal) []string {
+	codes := make([]string, 0, len(rates))
+	for code := range rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// refreshRates re-fetches the rates from the provider every interval, backing off
+// exponentially up to MaxBackoff while the provider fails. Updates are only signalled
+// when the fetched rates differ from the current ones.
+func (e *ExchangeRates) refreshRates(interval time.Duration, ret chan struct{}) {
+	delay := interval
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case interval = <-e.intervalCh:
+			delay = interval
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			timer.Reset(delay)
+		case <-timer.C:
+			changed, err := e.fetchRates()
+			if err != nil {
+				delay *= 2
+				if e.opts.Monitor.MaxBackoff > 0 && delay > e.opts.Monitor.MaxBackoff {
+					delay = e.opts.Monitor.MaxBackoff
+				}
+				e.log.Warn("Unable to refresh rates, backing off", "retry_in", delay, "error", err)
+				e.mutex.RLock()
+				if e.tooOld() {
+					e.log.Warn("Serving stale rates, they are older than the maximum age",
+						"updated_at", e.updatedAt, "max_age", e.opts.MaxAge)
+				}
+				e.mutex.RUnlock()
+				timer.Reset(delay)
+				continue
+			}
+
+			delay = interval
+			timer.Reset(delay)
+
+			if changed {
+				if !e.notify(ret) {
+					e.log.Info("MonitorRates received shutdown signal")
+					return
+				}
+			}
+		case <-e.changedCh:
+			if !e.notify(ret) {
+				e.log.Info("MonitorRates received shutdown signal")
+				return
+			}
+		case <-e.closeCh:
+			e.log.Info("MonitorRates received shutdown signal")
+			return
+		}
+	}
+}
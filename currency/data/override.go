@@ -0,0 +1,194 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OverrideSource is the source reported in the Route of an overridden rate
+const OverrideSource = "override"
+
+// ErrNoOverride is returned by ClearOverride when the pair is not overridden
+var ErrNoOverride = errors.New("no override is set for the pair")
+
+// Override pins the rate of a pair, the inverse pair is quoted at 1/Rate. Cross
+// rates through the pair keep following the rates of the sources.
+type Override struct {
+	Pair
+	Rate Decimal
+	// ExpiresAt is when the override ends, zero keeps it until it is cleared
+	ExpiresAt time.Time
+	// Reason is a note for other operators
+	Reason string
+	// CreatedBy identifies who set the override
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// active reports whether the override applies at now
+func (o Override) active(now time.Time) bool {
+	return o.ExpiresAt.IsZero() || now.Before(o.ExpiresAt)
+}
+
+// SetOverride pins the rate of a pair until it is cleared or expires, replacing
+// an override of the pair or its inverse. Subscribers are notified as for any
+// other change of the rates.
+func (e *ExchangeRates) SetOverride(o Override) (Override, error) {
+	if o.Base == o.Dest {
+		return Override{}, fmt.Errorf("the base and destination of an override must differ")
+	}
+	if o.Rate.Sign() <= 0 {
+		return Override{}, fmt.Errorf("the rate of an override must be positive")
+	}
+	now := time.Now()
+	if !o.active(now) {
+		return Override{}, fmt.Errorf("the override expires in the past")
+	}
+	if o.CreatedAt.IsZero() {
+		o.CreatedAt = now
+	}
+
+	e.mutex.Lock()
+	overrides := e.copyOverrides()
+	inverse := Pair{Base: o.Dest, Dest: o.Base}
+	delete(overrides, inverse)
+	e.stopOverrideTimer(inverse)
+	e.stopOverrideTimer(o.Pair)
+	overrides[o.Pair] = o
+	e.overrides = overrides
+	if !o.ExpiresAt.IsZero() {
+		if e.overrideTimers == nil {
+			e.overrideTimers = map[Pair]*time.Timer{}
+		}
+		e.overrideTimers[o.Pair] = time.AfterFunc(o.ExpiresAt.Sub(now), func() { e.expireOverride(o) })
+	}
+	e.publish()
+	e.mutex.Unlock()
+
+	e.log.Info("Rate override set", "pair", o.Base+"/"+o.Dest, "rate", o.Rate,
+		"expires_at", o.ExpiresAt, "reason", o.Reason, "created_by", o.CreatedBy)
+	e.signalChange()
+	return o, nil
+}
+
+// Overrides returns the active overrides ordered by pair
+func (e *ExchangeRates) Overrides() []Override {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	now := time.Now()
+	overrides := make([]Override, 0, len(e.overrides))
+	for _, o := range e.overrides {
+		if o.active(now) {
+			overrides = append(overrides, o)
+		}
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		if overrides[i].Base != overrides[j].Base {
+			return overrides[i].Base < overrides[j].Base
+		}
+		return overrides[i].Dest < overrides[j].Dest
+	})
+	return overrides
+}
+
+// ClearOverride removes the override of p or of its inverse, it returns
+// ErrNoOverride when neither is overridden
+func (e *ExchangeRates) ClearOverride(p Pair) error {
+	e.mutex.Lock()
+	o, found := e.overrides[p]
+	if !found || !o.active(time.Now()) {
+		o, found = e.overrides[Pair{Base: p.Dest, Dest: p.Base}]
+	}
+	if !found || !o.active(time.Now()) {
+		e.mutex.Unlock()
+		return ErrNoOverride
+	}
+	e.removeOverride(o.Pair)
+	e.mutex.Unlock()
+
+	e.log.Info("Rate override cleared", "pair", o.Base+"/"+o.Dest)
+	e.signalChange()
+	return nil
+}
+
+// expireOverride removes o when its expiry timer fires, unless it was replaced
+// or the rates were closed in the meantime
+func (e *ExchangeRates) expireOverride(o Override) {
+	e.mutex.Lock()
+	current, found := e.overrides[o.Pair]
+	_, timed := e.overrideTimers[o.Pair]
+	if !found || !timed || !current.CreatedAt.Equal(o.CreatedAt) {
+		e.mutex.Unlock()
+		return
+	}
+	e.removeOverride(o.Pair)
+	e.mutex.Unlock()
+
+	e.log.Info("Rate override expired", "pair", o.Base+"/"+o.Dest)
+	e.signalChange()
+}
+
+// removeOverride deletes the override of p and publishes the rates without it,
+// the caller must hold the write lock
+func (e *ExchangeRates) removeOverride(p Pair) {
+	overrides := e.copyOverrides()
+	delete(overrides, p)
+	e.overrides = overrides
+	e.stopOverrideTimer(p)
+	e.publish()
+}
+
+// copyOverrides returns a copy of the overrides to modify, the current map is
+// shared with the published snapshots. The caller must hold the write lock.
+func (e *ExchangeRates) copyOverrides() map[Pair]Override {
+	overrides := make(map[Pair]Override, len(e.overrides)+1)
+	for p, o := range e.overrides {
+		overrides[p] = o
+	}
+	return overrides
+}
+
+// stopOverrideTimer stops the expiry timer of p, the caller must hold the write lock
+func (e *ExchangeRates) stopOverrideTimer(p Pair) {
+	if t, ok := e.overrideTimers[p]; ok {
+		t.Stop()
+		delete(e.overrideTimers, p)
+	}
+}
+
+// stopOverrideTimers stops all expiry timers on Close, the caller must hold the write lock
+func (e *ExchangeRates) stopOverrideTimers() {
+	for p := range e.overrideTimers {
+		e.stopOverrideTimer(p)
+	}
+}
+
+// overrideQuote returns the active override of base/dest as a quote of the
+// current snapshot, the caller must hold the lock
+func (e *ExchangeRates) overrideQuote(base, dest string) (Quote, bool) {
+	rate, ok := overrideRate(e.overrides, base, dest, time.Now())
+	if !ok {
+		return Quote{}, false
+	}
+	return Quote{Rate: rate, Sequence: e.sequence, AsOf: e.asOf, Route: overrideRoute(base, dest), Overridden: true}, true
+}
+
+// overrideRate looks up the pinned rate of base/dest in overrides, inverting the
+// override of dest/base. Overrides which expired before now are ignored, a zero
+// now skips the check.
+func overrideRate(overrides map[Pair]Override, base, dest string, now time.Time) (Decimal, bool) {
+	if o, ok := overrides[Pair{Base: base, Dest: dest}]; ok && (now.IsZero() || o.active(now)) {
+		return o.Rate, true
+	}
+	if o, ok := overrides[Pair{Base: dest, Dest: base}]; ok && (now.IsZero() || o.active(now)) {
+		return NewDecimal(1).Quo(o.Rate), true
+	}
+	return Decimal{}, false
+}
+
+func overrideRoute(base, dest string) Route {
+	return Route{Path: []string{base, dest}, Sources: []string{OverrideSource}}
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"testing"
+	"time"
+)
+
+func TestOverrides(t *testing.T) {
+	opts := DefaultRatesOptions()
+	opts.Monitor.Mode = MonitorLive
+	opts.Monitor.Interval = time.Hour
+	rates, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(map[string]string{"USD": "1.1", "GBP": "0.8"}), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rates.Close()
+	updates := rates.MonitorRates()
+
+	rate, _ := ParseDecimal("1.25")
+	if _, err := rates.SetOverride(Override{Pair: Pair{Base: "USD", Dest: "EUR"}, Rate: NewDecimal(2)}); err != nil {
+		t.Fatal(err)
+	}
+	// replaces the override of the inverse pair
+	if _, err := rates.SetOverride(Override{Pair: Pair{Base: "EUR", Dest: "USD"}, Rate: rate, CreatedBy: "ops"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("expected an update when an override is set")
+	}
+
+	quote, err := rates.GetQuote("EUR", "USD")
+	if err != nil || quote.Rate.String() != "1.25" || !quote.Overridden || quote.Route.Sources[0] != OverrideSource {
+		t.Errorf("expected the pinned rate 1.25, got %+v, %v", quote, err)
+	}
+	if inverse, _ := rates.GetRate("USD", "EUR"); inverse.String() != "0.8" {
+		t.Errorf("expected the inverse of the pinned rate, got %s", inverse)
+	}
+	if cross, _ := rates.GetQuote("GBP", "USD"); cross.Overridden || cross.Rate.String() != "1.375" {
+		t.Errorf("expected cross rates to follow the sources, got %+v", cross)
+	}
+	if results := rates.GetRates([]Pair{{"EUR", "USD"}, {"EUR", "GBP"}}); !results[0].Overridden || results[1].Overridden {
+		t.Errorf("expected only EUR/USD to be overridden, got %+v", results)
+	}
+	if o := rates.Overrides(); len(o) != 1 || o[0].Pair != (Pair{"EUR", "USD"}) || o[0].CreatedBy != "ops" {
+		t.Errorf("expected the EUR/USD override, got %+v", o)
+	}
+
+	seq := rates.Sequence()
+	if err := rates.ClearOverride(Pair{Base: "USD", Dest: "EUR"}); err != nil {
+		t.Fatal(err)
+	}
+	if usd, _ := rates.GetRate("EUR", "USD"); usd.String() != "1.1" {
+		t.Errorf("expected the rate of the source once cleared, got %s", usd)
+	}
+	if err := rates.ClearOverride(Pair{Base: "EUR", Dest: "USD"}); !errors.Is(err, ErrNoOverride) {
+		t.Errorf("expected ErrNoOverride, got %v", err)
+	}
+
+	// snapshots keep the overrides they were published with
+	snaps, ok := rates.SnapshotsSince(seq - 1)
+	if !ok || len(snaps) != 2 {
+		t.Fatalf("expected 2 snapshots, got %d", len(snaps))
+	}
+	if q, _ := snaps[0].Quote("EUR", "USD"); !q.Overridden || q.Rate.String() != "1.25" {
+		t.Errorf("expected the overridden snapshot to keep the pinned rate, got %+v", q)
+	}
+	if q, _ := snaps[1].Quote("EUR", "USD"); q.Overridden {
+		t.Errorf("expected the snapshot after clearing not to be overridden, got %+v", q)
+	}
+}
+
+func TestOverrideExpires(t *testing.T) {
+	rates, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(map[string]string{"USD": "1.1"}), DefaultRatesOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rates.Close()
+
+	if _, err := rates.SetOverride(Override{Pair: Pair{Base: "EUR", Dest: "USD"}, Rate: NewDecimal(1), ExpiresAt: time.Now().Add(-time.Second)}); err == nil {
+		t.Error("expected an error for an override expiring in the past")
+	}
+	if _, err := rates.SetOverride(Override{Pair: Pair{Base: "EUR", Dest: "USD"}, Rate: NewDecimal(0)}); err == nil {
+		t.Error("expected an error for a rate which is not positive")
+	}
+
+	if _, err := rates.SetOverride(Override{Pair: Pair{Base: "EUR", Dest: "USD"}, Rate: NewDecimal(1), ExpiresAt: time.Now().Add(20 * time.Millisecond)}); err != nil {
+		t.Fatal(err)
+	}
+	seq := rates.Sequence()
+
+	deadline := time.Now().Add(time.Second)
+	for rates.Sequence() == seq && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if rates.Sequence() == seq {
+		t.Fatal("expected a new snapshot once the override expired")
+	}
+	if quote, _ := rates.GetQuote("EUR", "USD"); quote.Overridden || quote.Rate.String() != "1.1" {
+		t.Errorf("expected the rate of the source after expiry, got %+v", quote)
+	}
+	if o := rates.Overrides(); len(o) != 0 {
+		t.Errorf("expected no active overrides, got %+v", o)
+	}
+}
@@ -0,0 +1,245 @@
+package data
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ECBDailyURL is the European Central Bank feed with the latest reference rates
+const ECBDailyURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// RateProvider defines the behavior for loading a set of exchange rates
+// Implementations may fetch from a remote feed, read a local file, or hold fixed values.
+// All returned rates are quoted against the base of the RateSet.
+type RateProvider interface {
+	Rates() (RateSet, error)
+}
+
+// RateSet is a set of rates together with the time they were published, every
+// rate is the amount of a currency for one unit of Base
+type RateSet struct {
+	// Base is the currency the rates are quoted against, empty leaves it to the
+	// Source, which defaults to EUR
+	Base  string             `json:"base,omitempty"`
+	AsOf  time.Time          `json:"as_of"`
+	Rates map[string]Decimal `json:"rates"`
+}
+
+// defaultBase is the base of a RateSet that does not name one, the ECB feeds
+// quote every currency against EUR
+const defaultBase = "EUR"
+
+// base returns the currency the rates are quoted against
+func (rs RateSet) base() string {
+	if rs.Base == "" {
+		return defaultBase
+	}
+	return rs.Base
+}
+
+// ECBProvider fetches rates from the European Central Bank XML feed
+type ECBProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewECBProvider creates a provider for the ECB feed at url
+// if url is empty the daily reference rate feed is used
+func NewECBProvider(url string, timeout time.Duration) *ECBProvider {
+	if url == "" {
+		url = ECBDailyURL
+	}
+	return &ECBProvider{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *ECBProvider) Rates() (RateSet, error) {
+	resp, err := p.client.Get(p.url)
+	if err != nil {
+		return RateSet{}, fmt.Errorf("unable to fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return RateSet{}, fmt.Errorf("expected success code 200, got %d", resp.StatusCode)
+	}
+
+	return decodeECB(resp.Body)
+}
+
+// FileProvider reads rates from a local file
+// files ending in .json are decoded as an object of currency code to rate and
+// are dated by their modification time, anything else is decoded as an ECB
+// formatted XML document
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider creates a provider which reads rates from path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+func (p *FileProvider) Rates() (RateSet, error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return RateSet{}, fmt.Errorf("unable to open rate file: %w", err)
+	}
+	defer f.Close()
+
+	if !strings.EqualFold(filepath.Ext(p.path), ".json") {
+		return decodeECB(f)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return RateSet{}, fmt.Errorf("unable to stat rate file: %w", err)
+	}
+
+	rates, err := decodeJSON(f)
+	if err != nil {
+		return RateSet{}, err
+	}
+	return RateSet{AsOf: info.ModTime(), Rates: rates}, nil
+}
+
+// StaticProvider returns a fixed set of rates held in memory
+type StaticProvider struct {
+	rates map[string]string
+}
+
+// NewStaticProvider creates a provider which always returns rates, given as
+// decimal strings, dated at the time of the call
+func NewStaticProvider(rates map[string]string) *StaticProvider {
+	return &StaticProvider{rates: rates}
+}
+
+func (p *StaticProvider) Rates() (RateSet, error) {
+	ratesCopy := make(map[string]Decimal, len(p.rates))
+	for currencyCode, rate := range p.rates {
+		d, err := ParseDecimal(rate)
+		if err != nil {
+			return RateSet{}, fmt.Errorf("invalid rate for currency %s: %w", currencyCode, err)
+		}
+		ratesCopy[currencyCode] = d
+	}
+	return RateSet{AsOf: time.Now(), Rates: ratesCopy}, nil
+}
+
+type Cube struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+// decodeECB parses an ECB reference rate document, the daily feed has the same
+// layout as the history feeds with a single day so the latest day is used
+func decodeECB(r io.Reader) (RateSet, error) {
+	parsed := &HistoryCubes{}
+	if err := xml.NewDecoder(r).Decode(parsed); err != nil {
+		return RateSet{}, fmt.Errorf("unable to decode XML: %w", err)
+	}
+	if len(parsed.Days) == 0 {
+		return RateSet{}, fmt.Errorf("document contains no rates")
+	}
+
+	latest := parsed.Days[0]
+	for _, day := range parsed.Days[1:] {
+		if day.Time > latest.Time {
+			latest = day
+		}
+	}
+
+	asOf, err := time.Parse(DateLayout, latest.Time)
+	if err != nil {
+		return RateSet{}, fmt.Errorf("invalid date %q: %w", latest.Time, err)
+	}
+
+	rates, err := parseCubes(latest.Rates)
+	if err != nil {
+		return RateSet{}, err
+	}
+	return RateSet{AsOf: asOf, Rates: rates}, nil
+}
+
+// parseCubes converts the currency and rate attributes of cubes into a rate map,
+// the rates are kept as exact decimals
+func parseCubes(cubes []Cube) (map[string]Decimal, error) {
+	rates := make(map[string]Decimal, len(cubes))
+	for _, cube := range cubes {
+		rate, err := ParseDecimal(cube.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate for currency %s: %w", cube.Currency, err)
+		}
+		rates[cube.Currency] = rate
+	}
+	return rates, nil
+}
+
+// decodeJSON parses a JSON object of currency code to rate, rates may be
+// numbers or decimal strings
+func decodeJSON(r io.Reader) (map[string]Decimal, error) {
+	rates := map[string]Decimal{}
+	if err := json.NewDecoder(r).Decode(&rates); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON: %w", err)
+	}
+	return rates, nil
+}
+
+// NewProvider creates the RateProvider identified by name
+// supported names are "ecb", "file" and "static"; source is the URL for ecb
+// and the path for file, it is ignored for static
+func NewProvider(name, source string) (RateProvider, error) {
+	switch strings.ToLower(name) {
+	case "ecb":
+		return NewECBProvider(source, 10*time.Second), nil
+	case "file":
+		if source == "" {
+			return nil, fmt.Errorf("file provider requires a path")
+		}
+		return NewFileProvider(source), nil
+	case "static":
+		return NewStaticProvider(DefaultStaticRates), nil
+	default:
+		return nil, fmt.Errorf("unknown rate provider %q", name)
+	}
+}
+
+// DefaultStaticRates is a fixed set of EUR reference rates used by the static provider
+var DefaultStaticRates = map[string]string{
+	"USD": "1.0916",
+	"JPY": "163.17",
+	"BGN": "1.9558",
+	"CZK": "25.301",
+	"DKK": "7.4598",
+	"GBP": "0.83560",
+	"HUF": "401.15",
+	"PLN": "4.3140",
+	"RON": "4.9743",
+	"SEK": "11.4085",
+	"CHF": "0.9405",
+	"ISK": "150.10",
+	"NOK": "11.8330",
+	"TRY": "37.4330",
+	"AUD": "1.6332",
+	"BRL": "6.1427",
+	"CAD": "1.5052",
+	"CNY": "7.7620",
+	"HKD": "8.4810",
+	"IDR": "17134.33",
+	"ILS": "4.0965",
+	"INR": "91.7915",
+	"KRW": "1497.77",
+	"MXN": "21.5045",
+	"MYR": "4.6962",
+	"NZD": "1.8090",
+	"PHP": "62.982",
+	"SGD": "1.4312",
+	"THB": "36.502",
+	"ZAR": "19.2288",
+}
@@ -1,163 +1,535 @@
 package data
 
 import (
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/go-hclog"
-	"math/rand"
-	"net/http"
-	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// RatesOptions configures ExchangeRates
+type RatesOptions struct {
+	// Monitor controls how MonitorRates updates the rates after the initial load
+	Monitor MonitorOptions
+	// SnapshotPath is the file every rate set fetched from the provider is saved to
+	// and loaded from when the provider is unavailable at startup, empty disables snapshots
+	SnapshotPath string
+	// RetryInterval is the initial delay between background refresh attempts
+	// while running on snapshot data
+	RetryInterval time.Duration
+	// ReplaySize is the number of recent rate snapshots kept so subscribers can
+	// catch up on updates they missed, 0 disables the replay log
+	ReplaySize int
+	// SeriesSize is the number of published snapshots recorded for candles, 0
+	// disables the rate series
+	SeriesSize int
+	// SeriesRetention is how long published snapshots are recorded for candles,
+	// 0 keeps them until SeriesSize is reached
+	SeriesRetention time.Duration
+	// TapePath is the file every published rate set is recorded to, it can be
+	// replayed with MonitorReplay. Empty disables recording.
+	TapePath string
+	// Pivot is the currency the rates of every currency are kept against for
+	// snapshots, candles and the simulation, cross rates are computed from the
+	// quoted pairs
+	Pivot string
+	// Aggregation combines the quotes of sources quoting the same pair
+	Aggregation Aggregation
+	// MaxAge is how long rates in live mode are fresh after they were last
+	// fetched, older rates are reported as stale. 0 disables the check.
+	MaxAge time.Duration
+	// MaxChange is the largest relative change of a fetched rate against the
+	// current one that is accepted, 0 accepts any change
+	MaxChange float64
+	// ConfirmChange is the number of fetches or sources in a row that must report
+	// a rate rejected by MaxChange before it is accepted as a real step change,
+	// 0 never accepts it
+	ConfirmChange int
+}
+
+// DefaultRatesOptions returns options with the default monitor and no snapshot
+func DefaultRatesOptions() RatesOptions {
+	return RatesOptions{
+		Monitor:         DefaultMonitorOptions(),
+		RetryInterval:   30 * time.Second,
+		ReplaySize:      100,
+		SeriesSize:      20000,
+		SeriesRetention: 24 * time.Hour,
+		Pivot:           "EUR",
+		Aggregation:     AggregateMedian,
+		MaxAge:          time.Hour,
+		MaxChange:       0.2,
+		ConfirmChange:   3,
+	}
+}
+
 type ExchangeRates struct {
-	log     hclog.Logger
-	rates   map[string]float64
-	mutex   sync.RWMutex
-	closeCh chan struct{}  // Channel to signal shutdown
-	wg      sync.WaitGroup // WaitGroup to manage goroutines
+	log            hclog.Logger
+	sources        []Source
+	opts           RatesOptions
+	rates          map[string]Decimal // rates against the pivot, derived from graph
+	graph          *rateGraph         // quoted pairs of all sources, replaced on every change
+	asOf           time.Time          // publication time of the rates reported by the provider
+	updatedAt      time.Time          // last time the rates were fetched or loaded from a snapshot
+	sequence       uint64             // incremented every time the set of rates changes
+	recent         []RateSnapshot     // replay log of the last ReplaySize snapshots
+	series         *rateSeries        // rates of the published snapshots for candles, nil when disabled
+	recorder       *tapeRecorder      // records the published snapshots, nil when disabled
+	tape           []TapeFrame        // frames published in replay mode
+	stale          bool               // set while serving rates loaded from a snapshot
+	mutex          sync.RWMutex
+	fetchSuccesses atomic.Uint64
+	fetchFailures  atomic.Uint64
+	anomalies      atomic.Uint64        // rates rejected by filterQuotes
+	suspects       map[Pair]suspectRate // rates rejected by filterQuotes awaiting confirmation
+	intervalCh     chan time.Duration   // delivers a new monitor interval to the running monitor
+	overrides      map[Pair]Override    // pinned rates, replaced on every change so snapshots can share it
+	overrideTimers map[Pair]*time.Timer
+	changedCh      chan struct{}  // signals the monitor that the rates changed outside of it
+	closeCh        chan struct{}  // Channel to signal shutdown
+	wg             sync.WaitGroup // WaitGroup to manage goroutines
 }
 
-func NewRates(logger hclog.Logger) (*ExchangeRates, error) {
+// NewRates creates ExchangeRates with provider as the only source, see NewRatesFromSources
+func NewRates(logger hclog.Logger, provider RateProvider, opts RatesOptions) (*ExchangeRates, error) {
+	return NewRatesFromSources(logger, []Source{{Name: "default", Provider: provider}}, opts)
+}
+
+// NewRatesFromSources creates ExchangeRates and loads the initial rate set from
+// sources. If all sources fail and a snapshot is configured the rates are loaded
+// from the snapshot instead, flagged as stale and refreshed in the background
+// until a source recovers.
+func NewRatesFromSources(logger hclog.Logger, sources []Source, opts RatesOptions) (*ExchangeRates, error) {
+	if opts.Pivot == "" {
+		opts.Pivot = DefaultRatesOptions().Pivot
+	}
 	er := &ExchangeRates{
-		log:     logger,
-		rates:   map[string]float64{},
-		closeCh: make(chan struct{}),
+		log:        logger,
+		sources:    sources,
+		opts:       opts,
+		rates:      map[string]Decimal{},
+		graph:      &rateGraph{},
+		intervalCh: make(chan time.Duration, 1),
+		changedCh:  make(chan struct{}, 1),
+		closeCh:    make(chan struct{}),
+	}
+	if opts.SeriesSize > 0 {
+		er.series = newRateSeries(opts.SeriesRetention, opts.SeriesSize)
+	}
+	if opts.Monitor.Mode == MonitorReplay {
+		tape, err := LoadTape(opts.Monitor.Tape)
+		if err != nil {
+			return nil, err
+		}
+		er.tape = tape
+	}
+	if opts.TapePath != "" {
+		recorder, err := newTapeRecorder(opts.TapePath)
+		if err != nil {
+			return nil, err
+		}
+		er.recorder = recorder
 	}
 
-	err := er.getRates()
-	if err != nil {
+	_, err := er.fetchRates()
+	if err == nil {
+		return er, nil
+	}
+	if opts.SnapshotPath == "" {
+		er.closeRecorder()
 		return nil, err
 	}
 
+	snap, serr := loadSnapshot(opts.SnapshotPath)
+	if serr != nil {
+		logger.Error("Unable to load rate snapshot", "path", opts.SnapshotPath, "error", serr)
+		er.closeRecorder()
+		return nil, err
+	}
+
+	rs := snap.RateSet
+	er.graph = singleSourceGraph("snapshot", rs)
+	er.rates = er.graph.pivotRates(opts.Pivot)
+	er.asOf = rs.AsOf
+	er.updatedAt = snap.SavedAt
+	er.publish()
+	er.stale = true
+	logger.Warn("Serving stale rates from snapshot", "path", opts.SnapshotPath, "as_of", rs.AsOf)
+
+	er.wg.Add(1)
+	go er.recoverRates()
+
 	return er, nil
 }
 
-func (e *ExchangeRates) GetRate(base, dest string) (float64, error) {
+// Pair is a base and destination currency
+type Pair struct {
+	Base string
+	Dest string
+}
+
+// PairRate is the result of pricing a Pair, Err is set when no rate is available
+type PairRate struct {
+	Pair
+	Quote
+	Err error
+}
+
+// GetRates returns the rates for all pairs, in order, computed from a single
+// consistent set of rates. Every result carries the sequence and as-of time of
+// that set. A pair with the same base and destination has rate 1, overridden
+// pairs have their pinned rate.
+func (e *ExchangeRates) GetRates(pairs []Pair) []PairRate {
 	e.mutex.RLock()
 	defer e.mutex.RUnlock()
 
-	br, ok := e.rates[base]
-	if !ok {
-		return 0, fmt.Errorf("rate not found for currency %s", base)
+	results := make([]PairRate, len(pairs))
+	for i, p := range pairs {
+		results[i].Pair = p
+		results[i].Sequence = e.sequence
+		results[i].AsOf = e.asOf
+		results[i].Stale = e.isStale()
+		if quote, ok := e.overrideQuote(p.Base, p.Dest); ok {
+			results[i].Quote = quote
+			continue
+		}
+		if p.Base == p.Dest {
+			if _, ok := e.rates[p.Base]; ok {
+				results[i].Rate = NewDecimal(1)
+				results[i].Route = Route{Path: []string{p.Base}}
+				continue
+			}
+		}
+		results[i].Rate, results[i].Route, results[i].Err = e.graph.rate(p.Base, p.Dest)
 	}
+	return results
+}
 
-	dr, ok := e.rates[dest]
-	if !ok {
-		return 0, fmt.Errorf("rate not found for currency %s", dest)
-	}
+// GetRate returns the exact rate between base and dest, or its pinned rate
+func (e *ExchangeRates) GetRate(base, dest string) (Decimal, error) {
+	quote, err := e.GetQuote(base, dest)
+	return quote.Rate, err
+}
 
-	return dr / br, nil
+// Quote is a rate together with the snapshot of rates it was computed from
+type Quote struct {
+	Rate Decimal
+	// Sequence identifies the snapshot, it increases every time the rates change
+	Sequence uint64
+	// AsOf is the time the rates of the snapshot were published or simulated
+	AsOf time.Time
+	// Route is the path and the sources the rate was computed from
+	Route Route
+	// Stale is set when the snapshot is stale, see ExchangeRates.Stale
+	Stale bool
+	// Overridden is set when the rate is pinned by an Override, the route then
+	// names OverrideSource and the rate is never stale
+	Overridden bool
 }
 
-// MonitorRates periodically simulates rate changes and notifies via the returned channel
-func (e *ExchangeRates) MonitorRates(interval time.Duration) chan struct{} {
+// GetQuote returns the exact rate between base and dest with its snapshot
+// details, or the pinned rate of an overridden pair
+func (e *ExchangeRates) GetQuote(base, dest string) (Quote, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if quote, ok := e.overrideQuote(base, dest); ok {
+		return quote, nil
+	}
+	rate, route, err := e.graph.rate(base, dest)
+	if err != nil {
+		return Quote{}, err
+	}
+	return Quote{Rate: rate, Sequence: e.sequence, AsOf: e.asOf, Route: route, Stale: e.isStale()}, nil
+}
+
+// MonitorRates periodically updates the rates according to the configured
+// MonitorOptions and notifies via the returned channel whenever they change
+func (e *ExchangeRates) MonitorRates() chan struct{} {
 	ret := make(chan struct{})
 
+	e.mutex.RLock()
+	interval := e.opts.Monitor.Interval
+	e.mutex.RUnlock()
+	if interval <= 0 {
+		interval = DefaultMonitorOptions().Interval
+	}
+
 	e.wg.Add(1) // Increment WaitGroup counter
 
 	go func() {
 		defer e.wg.Done() // Decrement WaitGroup counter when goroutine completes
-		ticker := time.NewTicker(interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				e.mutex.Lock()
-				for k, v := range e.rates {
-					if k == "EUR" {
-						// Skip modifying EUR's rate
-						continue
-					}
-					// Simulate rate fluctuation
-					change := rand.Float64() / 10 // Up to 10%
-					direction := rand.Intn(2)     // 0 or 1
-
-					if direction == 0 {
-						// Decrease rate by up to 10%
-						change = 1 - change
-					} else {
-						// Increase rate by up to 10%
-						change = 1 + change
-					}
-
-					// Modify the rate
-					e.rates[k] = v * change
-				}
-				e.mutex.Unlock()
-
-				// Notify updates
-				ret <- struct{}{}
-			case <-e.closeCh:
-				e.log.Info("MonitorRates received shutdown signal")
-				return
-			}
+
+		switch e.opts.Monitor.Mode {
+		case MonitorLive:
+			e.refreshRates(interval, ret)
+		case MonitorReplay:
+			e.replayTape(ret)
+		default:
+			e.simulateRates(interval, ret)
 		}
 	}()
 
 	return ret
 }
 
-func (e *ExchangeRates) getRates() error {
-	resp, err := http.DefaultClient.Get("https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml")
-	if err != nil {
-		e.log.Error("Failed to fetch exchange rates", "error", err)
-		return err
+// notify signals an update on ch, it returns false if the service is shutting down
+func (e *ExchangeRates) notify(ch chan struct{}) bool {
+	select {
+	case ch <- struct{}{}:
+		return true
+	case <-e.closeCh:
+		return false
 	}
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("expected success code 200, got %d", resp.StatusCode)
+// fetchRates loads the rates from all sources and replaces the current set, it
+// reports whether any rate differs from the previous values. The fetch succeeds
+// when at least one source returned rates that reach the pivot. Every successful
+// fetch clears the stale flag and is written to the snapshot file if configured.
+func (e *ExchangeRates) fetchRates() (bool, error) {
+	graph, rs, err := e.fetchSources()
+	if err != nil {
+		e.fetchFailures.Add(1)
+		e.log.Error("Failed to fetch exchange rates", "error", err)
+		return false, err
 	}
-	defer resp.Body.Close()
+	e.fetchSuccesses.Add(1)
 
-	parsedCubes := &Cubes{}
-	err = xml.NewDecoder(resp.Body).Decode(&parsedCubes)
-	if err != nil {
-		e.log.Error("Failed to decode XML response", "error", err)
-		return err
+	if e.opts.SnapshotPath != "" {
+		if err := saveSnapshot(e.opts.SnapshotPath, rs); err != nil {
+			e.log.Error("Unable to save rate snapshot", "path", e.opts.SnapshotPath, "error", err)
+		}
 	}
+	rates := rs.Rates
 
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	for _, cube := range parsedCubes.CubeData {
-		rate, err := strconv.ParseFloat(cube.Rate, 64)
+
+	changed := len(rates) != len(e.rates)
+	for currencyCode, rate := range rates {
+		if old, ok := e.rates[currencyCode]; !ok || old.Cmp(rate) != 0 {
+			changed = true
+		}
+	}
+
+	if e.stale {
+		e.log.Info("Rates refreshed from provider, no longer stale", "as_of", rs.AsOf)
+	}
+
+	e.rates = rates
+	e.graph = graph
+	e.asOf = rs.AsOf
+	e.updatedAt = time.Now()
+	if changed {
+		e.publish()
+	}
+	e.stale = false
+	return changed, nil
+}
+
+// fetchSources fetches the rates of every source and combines them into a graph,
+// the returned rate set holds the rates against the pivot and is as of the oldest
+// source. Sources that fail are logged and left out.
+func (e *ExchangeRates) fetchSources() (*rateGraph, RateSet, error) {
+	e.mutex.RLock()
+	current := e.graph
+	e.mutex.RUnlock()
+
+	var sets []sourceRates
+	var errs []error
+	for _, src := range e.sources {
+		rs, err := src.Provider.Rates()
 		if err != nil {
-			return err
+			if len(e.sources) > 1 {
+				e.log.Warn("Source failed, using the remaining sources", "source", src.Name, "error", err)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", src.Name, err))
+			continue
 		}
+		if rs.Base == "" {
+			rs.Base = src.Base
+		}
+		rs = e.filterQuotes(src.Name, rs, current)
+		sets = append(sets, sourceRates{name: src.Name, weight: src.Weight, rates: rs})
+	}
+	if len(sets) == 0 {
+		return nil, RateSet{}, errors.Join(errs...)
+	}
+
+	graph := buildGraph(sets, e.opts.Aggregation)
+	rs := RateSet{Base: e.opts.Pivot, AsOf: sets[0].rates.AsOf, Rates: graph.pivotRates(e.opts.Pivot)}
+	if len(rs.Rates) < 2 {
+		return nil, RateSet{}, fmt.Errorf("no source quotes the pivot currency %s", e.opts.Pivot)
+	}
+	for _, set := range sets[1:] {
+		if set.rates.AsOf.Before(rs.AsOf) {
+			rs.AsOf = set.rates.AsOf
+		}
+	}
+	return graph, rs, nil
+}
+
+// recoverRates retries the provider in the background with exponential backoff
+// until a fetch succeeds, it is started when NewRates falls back to a snapshot.
+// The monitor is signalled on recovery so subscribers get the fresh rates right away.
+func (e *ExchangeRates) recoverRates() {
+	defer e.wg.Done()
+
+	delay := e.opts.RetryInterval
+	if delay <= 0 {
+		delay = DefaultRatesOptions().RetryInterval
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if !e.Stale() {
+				// refreshed by the live monitor in the meantime
+				return
+			}
+			if _, err := e.fetchRates(); err == nil {
+				e.signalChange()
+				return
+			}
+
+			delay *= 2
+			if e.opts.Monitor.MaxBackoff > 0 && delay > e.opts.Monitor.MaxBackoff {
+				delay = e.opts.Monitor.MaxBackoff
+			}
+			e.log.Warn("Rates are still stale, retrying", "retry_in", delay)
+			timer.Reset(delay)
+		case <-e.closeCh:
+			return
+		}
+	}
+}
+
+// Ready reports whether a rate set has been loaded
+func (e *ExchangeRates) Ready() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.sequence > 0
+}
 
-		e.rates[cube.Currency] = rate
+// FetchStats are the totals of provider fetches since the rates were created
+type FetchStats struct {
+	Successes uint64
+	Failures  uint64
+}
+
+// FetchStats returns the number of successful and failed provider fetches
+func (e *ExchangeRates) FetchStats() FetchStats {
+	return FetchStats{
+		Successes: e.fetchSuccesses.Load(),
+		Failures:  e.fetchFailures.Load(),
 	}
+}
+
+// Stale reports whether the rates were loaded from a snapshot and have not
+// been refreshed from the provider yet, or in live mode were last fetched
+// longer than MaxAge ago
+func (e *ExchangeRates) Stale() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.isStale()
+}
 
-	e.rates["EUR"] = 1.0 // Ensure EUR is always present with rate 1.0
-	return nil
+// isStale implements Stale, the caller must hold the lock
+func (e *ExchangeRates) isStale() bool {
+	return e.stale || e.tooOld()
+}
+
+// tooOld reports whether live rates are older than MaxAge, the caller must hold the lock
+func (e *ExchangeRates) tooOld() bool {
+	return e.opts.Monitor.Mode == MonitorLive && e.opts.MaxAge > 0 && time.Since(e.updatedAt) > e.opts.MaxAge
+}
+
+// Sequence returns the sequence number of the current snapshot of rates
+func (e *ExchangeRates) Sequence() uint64 {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.sequence
+}
+
+// UpdatedAt returns the last time the rates were fetched from the provider, for
+// rates loaded from a snapshot it is the time the snapshot was saved. Simulated
+// ticks do not change it, they are derived from the same provider data.
+func (e *ExchangeRates) UpdatedAt() time.Time {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.updatedAt
+}
+
+// AsOf returns the publication time of the current rates as reported by the provider
+func (e *ExchangeRates) AsOf() time.Time {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	return e.asOf
+}
+
+// crossRate computes the rate between base and dest from a set of rates against
+// the same currency
+func crossRate(rates map[string]Decimal, base, dest string) (Decimal, error) {
+	br, ok := rates[base]
+	if !ok || br.Sign() == 0 {
+		return Decimal{}, fmt.Errorf("rate not found for currency %s", base)
+	}
+
+	dr, ok := rates[dest]
+	if !ok {
+		return Decimal{}, fmt.Errorf("rate not found for currency %s", dest)
+	}
+
+	return dr.Quo(br), nil
+}
+
+// HasCurrency reports whether a rate is loaded for currency
+func (e *ExchangeRates) HasCurrency(currency string) bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	_, ok := e.rates[currency]
+	return ok
 }
 
 // GetAllRates returns a copy of all exchange rates in a thread-safe manner
-func (e *ExchangeRates) GetAllRates() map[string]float64 {
+func (e *ExchangeRates) GetAllRates() map[string]Decimal {
 	e.mutex.RLock()
 	defer e.mutex.RUnlock()
 
-	ratesCopy := make(map[string]float64)
+	ratesCopy := make(map[string]Decimal)
 	for currencyCode, rate := range e.rates {
 		ratesCopy[currencyCode] = rate
 	}
 	return ratesCopy
 }
 
-type Cubes struct {
-	CubeData []Cube `xml:"Cube>Cube>Cube"`
-}
-
-type Cube struct {
-	Currency string `xml:"currency,attr"`
-	Rate     string `xml:"rate,attr"`
+// signalChange tells the monitor to notify its subscribers of rates changed
+// outside of it, a signal already pending covers the change
+func (e *ExchangeRates) signalChange() {
+	select {
+	case e.changedCh <- struct{}{}:
+	default:
+	}
 }
 
 // Close gracefully shuts down the ExchangeRates service
 func (e *ExchangeRates) Close() {
 	close(e.closeCh) // Signal goroutines to stop
 	e.wg.Wait()      // Wait for all goroutines to finish
+	e.mutex.Lock()
+	e.closeRecorder()
+	e.stopOverrideTimers()
+	e.mutex.Unlock()
 	e.log.Info("ExchangeRates service closed gracefully")
 }
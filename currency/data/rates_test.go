@@ -1,16 +1,281 @@
 package data
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/go-hclog"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewRates(t *testing.T) {
-	tr, err := NewRates(hclog.Default())
+	tr, err := NewRates(hclog.Default(), NewStaticProvider(DefaultStaticRates), DefaultRatesOptions())
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	fmt.Printf("%#v", tr.rates)
 }
+
+func TestFileProvider(t *testing.T) {
+	for _, path := range []string{"testdata/eurofxref-daily.xml", "testdata/rates.json"} {
+		rs, err := NewFileProvider(path).Rates()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rates := rs.Rates
+
+		if path == "testdata/eurofxref-daily.xml" && rs.AsOf.Format(DateLayout) != "2024-10-15" {
+			t.Errorf("%s: expected rates as of 2024-10-15, got %v", path, rs.AsOf)
+		}
+		if rates["USD"].String() != "1.0892" {
+			t.Errorf("%s: expected USD rate 1.0892, got %v", path, rates["USD"])
+		}
+		if rates["JPY"].String() != "162.63" {
+			t.Errorf("%s: expected JPY rate 162.63, got %v", path, rates["JPY"])
+		}
+	}
+}
+
+// sequenceProvider returns each rate set in turn and then repeats the last one
+type sequenceProvider struct {
+	sets []map[string]string
+	n    int
+}
+
+func (p *sequenceProvider) Rates() (RateSet, error) {
+	set := p.sets[p.n]
+	if p.n < len(p.sets)-1 {
+		p.n++
+	}
+	return NewStaticProvider(set).Rates()
+}
+
+func TestMonitorRatesLive(t *testing.T) {
+	provider := &sequenceProvider{sets: []map[string]string{
+		{"USD": "1.09"},
+		{"USD": "1.09"},
+		{"USD": "1.10"},
+	}}
+
+	opts := DefaultRatesOptions()
+	opts.Monitor.Mode = MonitorLive
+	opts.Monitor.Interval = 10 * time.Millisecond
+	tr, err := NewRates(hclog.Default(), provider, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	updates := tr.MonitorRates()
+
+	select {
+	case <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("expected an update when the provider rates changed")
+	}
+
+	quote, _ := tr.GetQuote("EUR", "USD")
+	if quote.Rate.String() != "1.1" {
+		t.Errorf("expected refreshed rate 1.10, got %v", quote.Rate)
+	}
+	// the initial load and the change are snapshots, the unchanged fetch is not
+	if quote.Sequence != 2 {
+		t.Errorf("expected sequence 2, got %d", quote.Sequence)
+	}
+
+	select {
+	case <-updates:
+		t.Error("unexpected update when the provider rates did not change")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if tr.Sequence() != 2 {
+		t.Errorf("expected sequence to stay at 2, got %d", tr.Sequence())
+	}
+}
+
+func TestSimulationKeepsUpdatedAt(t *testing.T) {
+	opts := DefaultRatesOptions()
+	opts.Monitor.Interval = 5 * time.Millisecond
+	tr, err := NewRates(hclog.Default(), NewStaticProvider(DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	fetched := tr.UpdatedAt()
+	updates := tr.MonitorRates()
+	for i := 0; i < 2; i++ {
+		select {
+		case <-updates:
+		case <-time.After(time.Second):
+			t.Fatal("expected a simulated update")
+		}
+	}
+
+	if !tr.UpdatedAt().Equal(fetched) {
+		t.Errorf("expected simulated ticks to keep the fetch time %v, got %v", fetched, tr.UpdatedAt())
+	}
+	if !tr.AsOf().After(fetched) {
+		t.Errorf("expected simulated rates to be dated after the fetch, got %v", tr.AsOf())
+	}
+}
+
+// flakyProvider fails until up is set
+type flakyProvider struct {
+	up atomic.Bool
+}
+
+func (p *flakyProvider) Rates() (RateSet, error) {
+	if !p.up.Load() {
+		return RateSet{}, errors.New("provider unavailable")
+	}
+	return NewStaticProvider(map[string]string{"USD": "1.10"}).Rates()
+}
+
+func TestNewRatesFromSnapshot(t *testing.T) {
+	opts := DefaultRatesOptions()
+	opts.SnapshotPath = filepath.Join(t.TempDir(), "rates.json")
+	opts.RetryInterval = 10 * time.Millisecond
+
+	// a successful fetch writes the snapshot
+	tr, err := NewRates(hclog.Default(), NewFileProvider("testdata/eurofxref-daily.xml"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Close()
+
+	provider := &flakyProvider{}
+	tr, err = NewRates(hclog.Default(), provider, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	if !tr.Stale() {
+		t.Error("expected rates loaded from the snapshot to be stale")
+	}
+	if rate, _ := tr.GetRate("EUR", "USD"); rate.String() != "1.0892" {
+		t.Errorf("expected snapshot rate 1.0892, got %v", rate)
+	}
+	if tr.AsOf().Format(DateLayout) != "2024-10-15" {
+		t.Errorf("expected snapshot as of 2024-10-15, got %v", tr.AsOf())
+	}
+
+	provider.up.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for tr.Stale() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if tr.Stale() {
+		t.Fatal("expected the stale flag to clear once the provider recovered")
+	}
+	if rate, _ := tr.GetRate("EUR", "USD"); rate.String() != "1.1" {
+		t.Errorf("expected refreshed rate 1.10, got %v", rate)
+	}
+}
+
+func TestRecoveryNotifiesMonitor(t *testing.T) {
+	opts := DefaultRatesOptions()
+	opts.SnapshotPath = filepath.Join(t.TempDir(), "rates.json")
+	opts.RetryInterval = 10 * time.Millisecond
+	opts.Monitor.Mode = MonitorLive
+	opts.Monitor.Interval = time.Hour
+
+	tr, err := NewRates(hclog.Default(), NewFileProvider("testdata/eurofxref-daily.xml"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Close()
+
+	provider := &flakyProvider{}
+	tr, err = NewRates(hclog.Default(), provider, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	updates := tr.MonitorRates()
+	provider.up.Store(true)
+
+	// the live monitor would find the recovered rates unchanged on its next fetch
+	select {
+	case <-updates:
+	case <-time.After(time.Second):
+		t.Fatal("expected an update once the provider recovered")
+	}
+	if rate, _ := tr.GetRate("EUR", "USD"); rate.String() != "1.1" {
+		t.Errorf("expected recovered rate 1.10, got %v", rate)
+	}
+}
+
+func TestGetRates(t *testing.T) {
+	tr, err := NewRates(hclog.Default(), NewStaticProvider(map[string]string{"USD": "1.25", "GBP": "0.8"}), DefaultRatesOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := tr.GetRates([]Pair{{"EUR", "USD"}, {"USD", "GBP"}, {"USD", "USD"}, {"EUR", "XYZ"}})
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+
+	for i, expected := range []string{"1.25", "0.64", "1"} {
+		if results[i].Err != nil || results[i].Rate.String() != expected {
+			t.Errorf("%v: expected %s, got %v (%v)", results[i].Pair, expected, results[i].Rate, results[i].Err)
+		}
+		if results[i].Sequence != tr.Sequence() || !results[i].AsOf.Equal(tr.AsOf()) {
+			t.Errorf("%v: expected the snapshot %d as of %v, got %d as of %v",
+				results[i].Pair, tr.Sequence(), tr.AsOf(), results[i].Sequence, results[i].AsOf)
+		}
+	}
+	if results[3].Err == nil {
+		t.Error("expected an error for an unknown currency")
+	}
+}
+
+func TestSnapshotsSince(t *testing.T) {
+	provider := &sequenceProvider{sets: []map[string]string{
+		{"USD": "1.09"},
+		{"USD": "1.10"},
+		{"USD": "1.11"},
+	}}
+
+	opts := DefaultRatesOptions()
+	opts.ReplaySize = 2
+	tr, err := NewRates(hclog.Default(), provider, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := tr.fetchRates(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, ok := tr.SnapshotsSince(1)
+	if !ok || len(snapshots) != 2 {
+		t.Fatalf("expected the 2 snapshots after sequence 1, got %d (%v)", len(snapshots), ok)
+	}
+	for i, expected := range []string{"1.1", "1.11"} {
+		rate, err := snapshots[i].Rate("EUR", "USD")
+		if err != nil || rate.String() != expected {
+			t.Errorf("snapshot %d: expected %s, got %v (%v)", snapshots[i].Sequence, expected, rate, err)
+		}
+	}
+
+	if snapshots, ok := tr.SnapshotsSince(3); !ok || len(snapshots) != 0 {
+		t.Errorf("expected no snapshots after the current sequence, got %d (%v)", len(snapshots), ok)
+	}
+	if _, ok := tr.SnapshotsSince(0); ok {
+		t.Error("expected sequence 0 to be outside the replay log")
+	}
+	if _, ok := tr.SnapshotsSince(4); ok {
+		t.Error("expected an unknown sequence to be rejected")
+	}
+}
@@ -0,0 +1,88 @@
+package data
+
+import "time"
+
+// RateSnapshot is a complete set of rates identified by its sequence number
+type RateSnapshot struct {
+	Sequence uint64
+	AsOf     time.Time
+	// Rates are the rates against the pivot currency
+	Rates     map[string]Decimal
+	graph     *rateGraph
+	overrides map[Pair]Override // overrides active when the snapshot was published
+}
+
+// Rate computes the rate between base and dest in the snapshot
+func (s RateSnapshot) Rate(base, dest string) (Decimal, error) {
+	q, err := s.Quote(base, dest)
+	return q.Rate, err
+}
+
+// Quote computes the rate between base and dest in the snapshot through the
+// same path GetQuote took when the snapshot was current, including overrides
+func (s RateSnapshot) Quote(base, dest string) (Quote, error) {
+	if rate, ok := overrideRate(s.overrides, base, dest, time.Time{}); ok {
+		return Quote{Rate: rate, Sequence: s.Sequence, AsOf: s.AsOf, Route: overrideRoute(base, dest), Overridden: true}, nil
+	}
+	if s.graph == nil {
+		rate, err := crossRate(s.Rates, base, dest)
+		return Quote{Rate: rate, Sequence: s.Sequence, AsOf: s.AsOf}, err
+	}
+	rate, route, err := s.graph.rate(base, dest)
+	if err != nil {
+		return Quote{}, err
+	}
+	return Quote{Rate: rate, Sequence: s.Sequence, AsOf: s.AsOf, Route: route}, nil
+}
+
+// publish starts a new snapshot of the current rates and records it in the replay
+// log, the rate series and the tape, the caller must hold the write lock
+func (e *ExchangeRates) publish() {
+	e.sequence++
+
+	now := time.Now()
+	if e.series != nil {
+		e.series.record(now, e.rates)
+	}
+	if e.recorder != nil {
+		if err := e.recorder.record(now, e.opts.Pivot, e.rates); err != nil {
+			e.log.Error("Unable to record rates, recording stopped", "path", e.opts.TapePath, "error", err)
+			e.closeRecorder()
+		}
+	}
+
+	if e.opts.ReplaySize <= 0 {
+		return
+	}
+
+	rates := make(map[string]Decimal, len(e.rates))
+	for currencyCode, rate := range e.rates {
+		rates[currencyCode] = rate
+	}
+
+	if len(e.recent) >= e.opts.ReplaySize {
+		e.recent = append(e.recent[:0:0], e.recent[len(e.recent)-e.opts.ReplaySize+1:]...)
+	}
+	e.recent = append(e.recent, RateSnapshot{Sequence: e.sequence, AsOf: e.asOf, Rates: rates, graph: e.graph, overrides: e.overrides})
+}
+
+// SnapshotsSince returns the snapshots published after sequence, oldest first. It
+// returns false when some of them are no longer in the replay log, or sequence is
+// unknown, in which case the caller has to start again from the current rates.
+func (e *ExchangeRates) SnapshotsSince(sequence uint64) ([]RateSnapshot, bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if sequence > e.sequence {
+		return nil, false
+	}
+	if sequence == e.sequence {
+		return nil, true
+	}
+	if len(e.recent) == 0 || e.recent[0].Sequence > sequence+1 {
+		return nil, false
+	}
+
+	first := int(sequence + 1 - e.recent[0].Sequence)
+	return append([]RateSnapshot(nil), e.recent[first:]...), true
+}
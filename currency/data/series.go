@@ -0,0 +1,171 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ErrNoSeries is returned for candles when no rate series is recorded
+var ErrNoSeries = errors.New("rate series are not recorded")
+
+// ErrTooManyCandles is returned when a candle request spans more buckets than
+// MaxCandles
+var ErrTooManyCandles = errors.New("too many candles requested")
+
+// MaxCandles is the largest number of buckets a single Candles call can span
+const MaxCandles = 1500
+
+// Candle is the open, high, low and close rate of a pair during the interval
+// starting at Start, Samples is the number of rates published in it
+type Candle struct {
+	Start   time.Time
+	Open    float64
+	High    float64
+	Low     float64
+	Close   float64
+	Samples int
+}
+
+// rateSeries keeps the rate of every currency against the pivot at each published
+// snapshot. The columns of all currencies share the timestamps, so cross rates
+// can be computed point by point. A currency missing from a snapshot is NaN.
+type rateSeries struct {
+	retention time.Duration
+	size      int
+	times     []time.Time
+	values    map[string][]float64
+}
+
+func newRateSeries(retention time.Duration, size int) *rateSeries {
+	return &rateSeries{
+		retention: retention,
+		size:      size,
+		values:    make(map[string][]float64),
+	}
+}
+
+// record appends the rates published at to the series and drops the points that
+// are older than the retention or exceed the size
+func (s *rateSeries) record(at time.Time, rates map[string]Decimal) {
+	n := len(s.times)
+	s.times = append(s.times, at)
+	for currency, rate := range rates {
+		column, ok := s.values[currency]
+		for !ok && len(column) < n {
+			column = append(column, math.NaN())
+		}
+		s.values[currency] = append(column, rate.Float64())
+	}
+	var missing []string
+	for currency, column := range s.values {
+		if len(column) == n {
+			s.values[currency] = append(column, math.NaN())
+			missing = append(missing, currency)
+		}
+	}
+
+	drop := max(len(s.times)-s.size, 0)
+	if s.retention > 0 {
+		for drop < len(s.times) && at.Sub(s.times[drop]) > s.retention {
+			drop++
+		}
+	}
+	if drop == 0 {
+		return
+	}
+
+	// reslicing lets append move the remaining points to a new array once the
+	// capacity is used up, so the dropped points are released eventually
+	s.times = s.times[drop:]
+	for currency, column := range s.values {
+		s.values[currency] = column[drop:]
+	}
+	// forget currencies that are no longer published once their rates expired
+	for _, currency := range missing {
+		if allNaN(s.values[currency]) {
+			delete(s.values, currency)
+		}
+	}
+}
+
+// candles aggregates the rate of base/dest into buckets of interval between from
+// and to, buckets are aligned to multiples of interval and those without points
+// are left out
+func (s *rateSeries) candles(base, dest string, interval time.Duration, from, to time.Time) ([]Candle, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("candle interval must be positive")
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	from = from.Truncate(interval)
+	if to.Sub(from)/interval >= MaxCandles {
+		return nil, fmt.Errorf("%w: more than %d buckets of %s", ErrTooManyCandles, MaxCandles, interval)
+	}
+
+	baseRates, ok := s.values[base]
+	if !ok {
+		return nil, fmt.Errorf("rate not found for currency %s", base)
+	}
+	destRates, ok := s.values[dest]
+	if !ok {
+		return nil, fmt.Errorf("rate not found for currency %s", dest)
+	}
+
+	var candles []Candle
+	first := sort.Search(len(s.times), func(i int) bool { return !s.times[i].Before(from) })
+	for i := first; i < len(s.times) && s.times[i].Before(to); i++ {
+		if math.IsNaN(baseRates[i]) || math.IsNaN(destRates[i]) || baseRates[i] == 0 {
+			continue
+		}
+		rate := destRates[i] / baseRates[i]
+
+		start := s.times[i].Truncate(interval)
+		if len(candles) == 0 || !candles[len(candles)-1].Start.Equal(start) {
+			candles = append(candles, Candle{Start: start, Open: rate, High: rate, Low: rate})
+		}
+		c := &candles[len(candles)-1]
+		c.High = max(c.High, rate)
+		c.Low = min(c.Low, rate)
+		c.Close = rate
+		c.Samples++
+	}
+	return candles, nil
+}
+
+// oldest returns the time of the first point, the series must not be empty
+func (s *rateSeries) oldest() time.Time {
+	return s.times[0]
+}
+
+func allNaN(column []float64) bool {
+	for _, v := range column {
+		if !math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Candles returns the open, high, low and close rates of base/dest in buckets of
+// interval between from and to, computed from the snapshots published while the
+// service was running. A zero from starts at the oldest recorded snapshot. Only
+// the snapshots within SeriesRetention and SeriesSize are kept.
+func (e *ExchangeRates) Candles(base, dest string, interval time.Duration, from, to time.Time) ([]Candle, error) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	if e.series == nil {
+		return nil, ErrNoSeries
+	}
+	if len(e.series.times) == 0 {
+		return nil, nil
+	}
+	if from.IsZero() {
+		from = e.series.oldest()
+	}
+	return e.series.candles(base, dest, interval, from, to)
+}
@@ -0,0 +1,149 @@
+package data
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// seriesRates returns a rate set with USD and GBP at the given rates against EUR
+func seriesRates(usd, gbp int64) map[string]Decimal {
+	return map[string]Decimal{"EUR": NewDecimal(1), "USD": NewDecimal(usd), "GBP": NewDecimal(gbp)}
+}
+
+func TestSeriesCandles(t *testing.T) {
+	s := newRateSeries(0, 100)
+	start := time.Date(2024, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	// two points in the first minute, none in the second and three in the third
+	s.record(start.Add(10*time.Second), seriesRates(2, 4))
+	s.record(start.Add(50*time.Second), seriesRates(4, 4))
+	s.record(start.Add(2*time.Minute), seriesRates(1, 4))
+	s.record(start.Add(2*time.Minute+20*time.Second), seriesRates(8, 4))
+	s.record(start.Add(2*time.Minute+40*time.Second), seriesRates(2, 4))
+
+	candles, err := s.candles("EUR", "USD", time.Minute, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Candle{
+		{Start: start, Open: 2, High: 4, Low: 2, Close: 4, Samples: 2},
+		{Start: start.Add(2 * time.Minute), Open: 1, High: 8, Low: 1, Close: 2, Samples: 3},
+	}
+	if len(candles) != len(expected) {
+		t.Fatalf("expected %d candles, got %+v", len(expected), candles)
+	}
+	for i := range expected {
+		if candles[i] != expected[i] {
+			t.Errorf("candle %d: expected %+v, got %+v", i, expected[i], candles[i])
+		}
+	}
+
+	// cross rates are computed point by point
+	cross, err := s.candles("GBP", "USD", 5*time.Minute, start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cross) != 1 || cross[0].Open != 0.5 || cross[0].High != 2 || cross[0].Low != 0.25 || cross[0].Close != 0.5 {
+		t.Errorf("expected a single GBP/USD candle from 0.5 to 0.5 between 0.25 and 2, got %+v", cross)
+	}
+
+	// from is aligned to the interval, to is exclusive
+	bounded, err := s.candles("EUR", "USD", time.Minute, start.Add(30*time.Second), start.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bounded) != 1 || bounded[0].Samples != 2 {
+		t.Errorf("expected only the first minute, got %+v", bounded)
+	}
+}
+
+func TestSeriesCandleErrors(t *testing.T) {
+	s := newRateSeries(0, 100)
+	start := time.Date(2024, 10, 16, 8, 0, 0, 0, time.UTC)
+	s.record(start, seriesRates(2, 4))
+
+	if _, err := s.candles("EUR", "XYZ", time.Minute, start, start.Add(time.Hour)); err == nil {
+		t.Error("expected an error for an unknown currency")
+	}
+	if _, err := s.candles("EUR", "USD", time.Minute, start, start); err == nil {
+		t.Error("expected an error for an empty range")
+	}
+	_, err := s.candles("EUR", "USD", time.Minute, start, start.Add(MaxCandles*time.Minute))
+	if !errors.Is(err, ErrTooManyCandles) {
+		t.Errorf("expected ErrTooManyCandles, got %v", err)
+	}
+}
+
+func TestSeriesBounds(t *testing.T) {
+	start := time.Date(2024, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	// the size drops the oldest points
+	s := newRateSeries(0, 3)
+	for i := 0; i < 5; i++ {
+		s.record(start.Add(time.Duration(i)*time.Second), seriesRates(int64(i+1), 4))
+	}
+	if len(s.times) != 3 || !s.times[0].Equal(start.Add(2*time.Second)) || s.values["USD"][0] != 3 {
+		t.Errorf("expected the last 3 points, got %v %v", s.times, s.values["USD"])
+	}
+
+	// the retention drops the points that are too old
+	s = newRateSeries(time.Minute, 100)
+	s.record(start, seriesRates(1, 4))
+	s.record(start.Add(30*time.Second), seriesRates(2, 4))
+	s.record(start.Add(90*time.Second), seriesRates(3, 4))
+	if len(s.times) != 2 || s.values["USD"][0] != 2 {
+		t.Errorf("expected the points of the last minute, got %v %v", s.times, s.values["USD"])
+	}
+
+	// a currency that is no longer published is forgotten once its points expired,
+	// one that appears later is padded
+	s.record(start.Add(100*time.Second), map[string]Decimal{"EUR": NewDecimal(1), "CHF": NewDecimal(1)})
+	s.record(start.Add(200*time.Second), map[string]Decimal{"EUR": NewDecimal(1), "CHF": NewDecimal(1)})
+	if _, ok := s.values["USD"]; ok {
+		t.Errorf("expected USD to be forgotten, got %v", s.values["USD"])
+	}
+	if len(s.values["CHF"]) != len(s.times) {
+		t.Errorf("expected the CHF column to match the timestamps, got %v and %v", s.values["CHF"], s.times)
+	}
+}
+
+func TestCandlesFromPublishedRates(t *testing.T) {
+	opts := DefaultRatesOptions()
+	opts.Monitor.Interval = time.Millisecond
+	rates, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rates.Close()
+	updates := rates.MonitorRates()
+	for i := 0; i < 5; i++ {
+		<-updates
+	}
+
+	candles, err := rates.Candles("EUR", "USD", time.Hour, time.Time{}, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := 0
+	for _, c := range candles {
+		samples += c.Samples
+		if c.Low > c.Open || c.Low > c.Close || c.High < c.Open || c.High < c.Close {
+			t.Errorf("expected open and close between low and high, got %+v", c)
+		}
+	}
+	if samples < 6 {
+		t.Errorf("expected the initial load and every tick to be recorded, got %d samples", samples)
+	}
+
+	opts.SeriesSize = 0
+	disabled, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := disabled.Candles("EUR", "USD", time.Hour, time.Time{}, time.Now()); !errors.Is(err, ErrNoSeries) {
+		t.Errorf("expected ErrNoSeries, got %v", err)
+	}
+}
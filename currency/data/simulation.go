@@ -0,0 +1,80 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// year is the period the drift and volatility of GBM are quoted for
+const year = 365 * 24 * time.Hour
+
+// SimulationModel moves the rates in simulate mode. The monitor calls it once per
+// tick for every currency other than the pivot, in order of the currency code, so
+// a model drawing all randomness from rnd repeats the same rates for the same seed.
+type SimulationModel interface {
+	// Next returns the rate of currency against the pivot dt after rate
+	Next(rnd *rand.Rand, currency string, rate float64, dt time.Duration) float64
+}
+
+// UniformWalk moves every rate up or down by a uniformly distributed change of
+// up to Volatility per tick, independent of the interval
+type UniformWalk struct {
+	Volatility float64
+}
+
+// Next implements SimulationModel
+func (u UniformWalk) Next(rnd *rand.Rand, _ string, rate float64, _ time.Duration) float64 {
+	change := rnd.Float64() * u.Volatility
+	if rnd.Intn(2) == 0 {
+		return rate * (1 - change)
+	}
+	return rate * (1 + change)
+}
+
+// GBMParams are the annualised drift and volatility of a currency against the pivot
+type GBMParams struct {
+	Drift      float64
+	Volatility float64
+}
+
+// GBM is a geometric Brownian motion, the rates follow a log-normal random walk
+// scaled by the interval between two ticks
+type GBM struct {
+	// Default applies to every currency missing from Currencies
+	Default    GBMParams
+	Currencies map[string]GBMParams
+}
+
+// Next implements SimulationModel
+func (g GBM) Next(rnd *rand.Rand, currency string, rate float64, dt time.Duration) float64 {
+	p, ok := g.Currencies[currency]
+	if !ok {
+		p = g.Default
+	}
+
+	t := dt.Seconds() / year.Seconds()
+	return rate * math.Exp((p.Drift-p.Volatility*p.Volatility/2)*t+p.Volatility*math.Sqrt(t)*rnd.NormFloat64())
+}
+
+// ModelKind selects the SimulationModel created from the configuration
+type ModelKind string
+
+const (
+	// ModelUniform is a UniformWalk of the monitor volatility
+	ModelUniform ModelKind = "uniform"
+	// ModelGBM is a geometric Brownian motion
+	ModelGBM ModelKind = "gbm"
+)
+
+// ParseModelKind converts a configuration value into a ModelKind
+func ParseModelKind(s string) (ModelKind, error) {
+	switch k := ModelKind(strings.ToLower(s)); k {
+	case ModelUniform, ModelGBM:
+		return k, nil
+	default:
+		return "", fmt.Errorf("unknown simulation model %q", s)
+	}
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// simulate runs the monitor of a static rate set for ticks updates and returns
+// the published snapshots
+func simulate(t *testing.T, opts RatesOptions, ticks int) []RateSnapshot {
+	t.Helper()
+
+	rates, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates := rates.MonitorRates()
+	for i := 0; i < ticks; i++ {
+		<-updates
+	}
+	rates.Close()
+
+	snapshots, ok := rates.SnapshotsSince(0)
+	if !ok || len(snapshots) < ticks+1 {
+		t.Fatalf("expected at least %d snapshots, got %d", ticks+1, len(snapshots))
+	}
+	return snapshots[:ticks+1]
+}
+
+func TestSeededSimulationRepeats(t *testing.T) {
+	for name, model := range map[string]SimulationModel{
+		"uniform": nil,
+		"gbm":     GBM{Default: GBMParams{Drift: 0.05, Volatility: 20}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			opts := DefaultRatesOptions()
+			opts.Monitor.Interval = time.Millisecond
+			opts.Monitor.Model = model
+			opts.Monitor.Seed = 42
+
+			first := simulate(t, opts, 10)
+			second := simulate(t, opts, 10)
+			for i := range first {
+				if len(first[i].Rates) != len(second[i].Rates) {
+					t.Fatalf("snapshot %d: expected the same currencies, got %v and %v", i, first[i].Rates, second[i].Rates)
+				}
+				for code, rate := range first[i].Rates {
+					if rate.Cmp(second[i].Rates[code]) != 0 {
+						t.Errorf("snapshot %d: expected %s %s in both runs, got %s", i, code, rate, second[i].Rates[code])
+					}
+				}
+			}
+			if first[0].Rates["USD"].Cmp(first[10].Rates["USD"]) == 0 {
+				t.Error("expected the simulation to move the USD rate")
+			}
+		})
+	}
+}
+
+func TestGBM(t *testing.T) {
+	model := GBM{
+		Default:    GBMParams{Volatility: 0.1},
+		Currencies: map[string]GBMParams{"USD": {Drift: math.Log(2)}},
+	}
+	rnd := rand.New(rand.NewSource(1))
+
+	// without volatility the rate grows by the drift only
+	if got := model.Next(rnd, "USD", 1, year); math.Abs(got-2) > 1e-9 {
+		t.Errorf("expected USD to double in a year, got %v", got)
+	}
+	if got := model.Next(rnd, "GBP", 1, 0); got != 1 {
+		t.Errorf("expected no change without time passing, got %v", got)
+	}
+	if got := model.Next(rnd, "GBP", 1, time.Hour); got == 1 || got <= 0 {
+		t.Errorf("expected GBP to move and stay positive, got %v", got)
+	}
+}
+
+func TestTapeRecordAndReplay(t *testing.T) {
+	tape := filepath.Join(t.TempDir(), "tape", "rates.jsonl")
+
+	opts := DefaultRatesOptions()
+	opts.Monitor.Interval = time.Millisecond
+	opts.Monitor.Seed = 7
+	opts.TapePath = tape
+	recorded := simulate(t, opts, 5)
+
+	frames, err := LoadTape(tape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) < len(recorded) {
+		t.Fatalf("expected every published snapshot on the tape, got %d frames for %d snapshots", len(frames), len(recorded))
+	}
+
+	replay := DefaultRatesOptions()
+	replay.Monitor.Mode = MonitorReplay
+	replay.Monitor.Tape = tape
+	replay.Monitor.Speed = 10
+	// the initial load of the provider precedes the frames of the tape
+	replayed := simulate(t, replay, len(frames))[1:]
+
+	for i := range recorded {
+		for code, rate := range recorded[i].Rates {
+			if rate.Cmp(replayed[i].Rates[code]) != 0 {
+				t.Errorf("frame %d: expected %s %s, got %s", i, code, rate, replayed[i].Rates[code])
+			}
+		}
+	}
+
+	replay.Monitor.Tape = filepath.Join(t.TempDir(), "missing.jsonl")
+	if _, err := NewRates(hclog.NewNullLogger(), NewStaticProvider(DefaultStaticRates), replay); err == nil {
+		t.Error("expected an error for a missing tape")
+	}
+}
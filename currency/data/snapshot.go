@@ -0,0 +1,58 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshot is the on-disk representation of the last rate set fetched from the provider
+type snapshot struct {
+	RateSet
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// saveSnapshot writes rs to path, the file is replaced atomically so a crash
+// while writing never leaves a truncated snapshot behind
+func saveSnapshot(path string, rs RateSet) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("unable to create snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	err = json.NewEncoder(tmp).Encode(snapshot{RateSet: rs, SavedAt: time.Now()})
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadSnapshot reads the snapshot stored at path
+func loadSnapshot(path string) (snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return snapshot{}, fmt.Errorf("unable to open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	s := snapshot{}
+	if err := json.NewDecoder(f).Decode(&s); err != nil {
+		return snapshot{}, fmt.Errorf("unable to decode snapshot: %w", err)
+	}
+	if len(s.Rates) == 0 {
+		return snapshot{}, fmt.Errorf("snapshot contains no rates")
+	}
+
+	return s, nil
+}
@@ -0,0 +1,157 @@
+package data
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TapeFrame is a rate set published at At, a tape is a file of frames written
+// as one JSON object per line
+type TapeFrame struct {
+	At time.Time `json:"at"`
+	// Base is the pivot currency of the rates, empty is EUR
+	Base  string             `json:"base,omitempty"`
+	Rates map[string]Decimal `json:"rates"`
+}
+
+// tapeRecorder appends every published rate set to a tape
+type tapeRecorder struct {
+	f   *os.File
+	enc *json.Encoder
+}
+
+// newTapeRecorder creates the tape at path, replacing an existing one
+func newTapeRecorder(path string) (*tapeRecorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("unable to create tape directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create tape: %w", err)
+	}
+	return &tapeRecorder{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// record writes a frame, every frame is written through so a tape stays usable
+// when the service is killed
+func (t *tapeRecorder) record(at time.Time, base string, rates map[string]Decimal) error {
+	return t.enc.Encode(TapeFrame{At: at, Base: base, Rates: rates})
+}
+
+func (t *tapeRecorder) close() error {
+	return t.f.Close()
+}
+
+// closeRecorder stops recording the tape, the caller must hold the write lock
+// unless the rates are not shared yet
+func (e *ExchangeRates) closeRecorder() {
+	if e.recorder == nil {
+		return
+	}
+	if err := e.recorder.close(); err != nil {
+		e.log.Error("Unable to close the rate tape", "path", e.opts.TapePath, "error", err)
+	}
+	e.recorder = nil
+}
+
+// LoadTape reads the frames of the tape at path
+func LoadTape(path string) ([]TapeFrame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open tape: %w", err)
+	}
+	defer f.Close()
+
+	var frames []TapeFrame
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		frame := TapeFrame{}
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("unable to decode tape line %d: %w", line, err)
+		}
+		if len(frame.Rates) == 0 {
+			return nil, fmt.Errorf("tape line %d contains no rates", line)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read tape: %w", err)
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("tape contains no frames")
+	}
+
+	return frames, nil
+}
+
+// replayTape publishes the frames of the tape with the delays they were recorded
+// with divided by Speed, after the last frame only overrides change the rates
+func (e *ExchangeRates) replayTape(ret chan struct{}) {
+	speed := e.opts.Monitor.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for i := 0; i < len(e.tape); {
+		select {
+		case <-timer.C:
+			frame := e.tape[i]
+			graph := singleSourceGraph("tape", RateSet{Base: frame.Base, Rates: frame.Rates})
+
+			e.mutex.Lock()
+			e.graph = graph
+			e.rates = graph.pivotRates(e.opts.Pivot)
+			e.asOf = time.Now()
+			e.publish()
+			e.mutex.Unlock()
+
+			if i++; i < len(e.tape) {
+				timer.Reset(time.Duration(float64(e.tape[i].At.Sub(frame.At)) / speed))
+			}
+
+			if !e.notify(ret) {
+				e.log.Info("MonitorRates received shutdown signal")
+				return
+			}
+		case <-e.intervalCh:
+			// the tape sets the pace of the updates
+		case <-e.changedCh:
+			if !e.notify(ret) {
+				e.log.Info("MonitorRates received shutdown signal")
+				return
+			}
+		case <-e.closeCh:
+			e.log.Info("MonitorRates received shutdown signal")
+			return
+		}
+	}
+
+	e.log.Info("Finished replaying the rate tape", "frames", len(e.tape))
+
+	// the last frame stays current, overrides can still change it
+	for {
+		select {
+		case <-e.changedCh:
+			if !e.notify(ret) {
+				e.log.Info("MonitorRates received shutdown signal")
+				return
+			}
+		case <-e.intervalCh:
+		case <-e.closeCh:
+			e.log.Info("MonitorRates received shutdown signal")
+			return
+		}
+	}
+}
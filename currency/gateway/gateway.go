@@ -0,0 +1,253 @@
+// Package gateway serves the Currency service over HTTP with JSON bodies for
+// clients that cannot call gRPC, rate subscriptions are streamed as Server-Sent Events
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// forwardedHeaders are passed on to the gRPC server as metadata, they carry the
+// API key of the client and its request ID
+var forwardedHeaders = []string{"x-api-key", "authorization", "x-request-id"}
+
+// requestIDHeader is returned on every response with the ID the server assigned
+const requestIDHeader = "X-Request-Id"
+
+// Options configures the gateway
+type Options struct {
+	// KeepAlive is how often an idle event stream gets a comment and the
+	// subscriptions a heartbeat, so neither proxies nor the server drop them
+	KeepAlive time.Duration
+}
+
+// DefaultOptions returns a keep alive of 15 seconds
+func DefaultOptions() Options {
+	return Options{KeepAlive: 15 * time.Second}
+}
+
+// Gateway translates HTTP requests to calls of a Currency client
+type Gateway struct {
+	log     hclog.Logger
+	client  protos.CurrencyClient
+	opts    Options
+	mux     *http.ServeMux
+	marshal protojson.MarshalOptions
+	closeCh chan struct{}
+	once    sync.Once
+}
+
+// New creates a gateway calling client
+func New(l hclog.Logger, client protos.CurrencyClient, opts Options) *Gateway {
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = DefaultOptions().KeepAlive
+	}
+
+	g := &Gateway{
+		log:     l,
+		client:  client,
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		closeCh: make(chan struct{}),
+	}
+	g.mux.HandleFunc("GET /rates/{base}/{dest}", g.getRate)
+	g.mux.HandleFunc("GET /rates/{base}/{dest}/candles", g.getCandles)
+	g.mux.HandleFunc("GET /rates/stream", g.streamRates)
+	g.mux.HandleFunc("GET /currencies", g.listCurrencies)
+	return g
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// Close ends all event streams, an http.Server does not close them on Shutdown
+func (g *Gateway) Close() {
+	g.once.Do(func() {
+		close(g.closeCh)
+	})
+}
+
+// getRate returns the current rate of a currency pair
+func (g *Gateway) getRate(w http.ResponseWriter, r *http.Request) {
+	var header metadata.MD
+	resp, err := g.client.GetRate(outgoingContext(r), &protos.RateRequest{
+		BaseCode:        strings.ToUpper(r.PathValue("base")),
+		DestinationCode: strings.ToUpper(r.PathValue("dest")),
+	}, grpc.Header(&header))
+	setRequestID(w, header)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, resp)
+}
+
+// candleIntervals are the values of the interval query parameter of getCandles
+var candleIntervals = map[string]protos.CandleInterval{
+	"1m": protos.CandleInterval_ONE_MINUTE,
+	"5m": protos.CandleInterval_FIVE_MINUTES,
+	"1h": protos.CandleInterval_ONE_HOUR,
+}
+
+// getCandles returns the candles of a currency pair, the query takes the interval
+// as 1m, 5m or 1h and the optional bounds from and to as RFC 3339 timestamps:
+//
+//	GET /rates/EUR/USD/candles?interval=5m&from=2024-10-16T08:00:00Z
+func (g *Gateway) getCandles(w http.ResponseWriter, r *http.Request) {
+	req, err := candlesRequest(r)
+	if err != nil {
+		g.writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	var header metadata.MD
+	resp, err := g.client.GetCandles(outgoingContext(r), req, grpc.Header(&header))
+	setRequestID(w, header)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, resp)
+}
+
+// candlesRequest creates the GetCandles request for the path and query of r
+func candlesRequest(r *http.Request) (*protos.CandlesRequest, error) {
+	query := r.URL.Query()
+	req := &protos.CandlesRequest{
+		BaseCode:        strings.ToUpper(r.PathValue("base")),
+		DestinationCode: strings.ToUpper(r.PathValue("dest")),
+	}
+
+	if v := query.Get("interval"); v != "" {
+		interval, ok := candleIntervals[v]
+		if !ok {
+			return nil, fmt.Errorf("interval %q is not one of 1m, 5m, 1h", v)
+		}
+		req.Interval = interval
+	}
+	for name, field := range map[string]**timestamppb.Timestamp{
+		"from": &req.From,
+		"to":   &req.To,
+	} {
+		if v := query.Get(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+			*field = timestamppb.New(t)
+		}
+	}
+	return req, nil
+}
+
+// listCurrencies returns the codes of all supported currencies
+func (g *Gateway) listCurrencies(w http.ResponseWriter, r *http.Request) {
+	var header metadata.MD
+	resp, err := g.client.ListCurrencies(outgoingContext(r), &protos.Empty{}, grpc.Header(&header))
+	setRequestID(w, header)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	g.writeJSON(w, http.StatusOK, resp)
+}
+
+// writeJSON writes msg as the JSON response body
+func (g *Gateway) writeJSON(w http.ResponseWriter, code int, msg proto.Message) {
+	body, err := g.marshal.Marshal(msg)
+	if err != nil {
+		g.log.Error("Unable to marshal response", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
+
+// writeError writes the gRPC status of err as a google.rpc.Status JSON body with
+// the matching HTTP status code
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	g.writeJSON(w, HTTPStatus(st.Code()), st.Proto())
+}
+
+// HTTPStatus maps a gRPC status code to the HTTP status code of the response,
+// following google.rpc.Code
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// outgoingContext returns the context of r with the forwarded headers as metadata
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, h := range forwardedHeaders {
+		if v := r.Header.Values(h); len(v) > 0 {
+			md.Append(h, v...)
+		}
+	}
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
+
+// setRequestID copies the request ID the server returned to the response headers
+func setRequestID(w http.ResponseWriter, header metadata.MD) {
+	if ids := header.Get(requestIDHeader); len(ids) > 0 {
+		w.Header().Set(requestIDHeader, ids[0])
+	}
+}
+
+// parsePair parses a pair written as BASE/DEST or BASE-DEST
+func parsePair(s string) (*protos.RateRequest, error) {
+	base, dest, ok := strings.Cut(s, "/")
+	if !ok {
+		base, dest, ok = strings.Cut(s, "-")
+	}
+	if !ok {
+		return nil, fmt.Errorf("pair %q is not written as BASE/DEST", s)
+	}
+	return &protos.RateRequest{
+		BaseCode:        strings.ToUpper(base),
+		DestinationCode: strings.ToUpper(dest),
+	}, nil
+}
@@ -0,0 +1,329 @@
+package gateway
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"github.com/kahvecikaan/buildingMicroservices/currency/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// newTestGateway serves a gateway in front of a Currency server requiring the API
+// key "key", its simulated rates change every 10ms. Everything is stopped when the
+// test ends.
+func newTestGateway(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	opts := data.DefaultRatesOptions()
+	opts.Monitor.Interval = 10 * time.Millisecond
+	rates, err := data.NewRates(hclog.NewNullLogger(), data.NewStaticProvider(data.DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := server.NewCurrency(hclog.NewNullLogger(), rates, data.NewHistoricalRates(hclog.NewNullLogger()), server.DefaultOptions())
+
+	auth := server.NewAuthenticator(hclog.NewNullLogger(), &server.Credentials{
+		Clients: []server.ClientCredentials{{ID: "test", KeyHashes: []string{server.HashKey("key")}}},
+	})
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(server.UnaryLogging(hclog.NewNullLogger()), auth.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(server.StreamLogging(hclog.NewNullLogger()), auth.StreamInterceptor()))
+	protos.RegisterCurrencyServer(gs, c)
+
+	conn, err := ServeInProcess(hclog.NewNullLogger(), gs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gw := New(hclog.NewNullLogger(), protos.NewCurrencyClient(conn), Options{KeepAlive: 50 * time.Millisecond})
+	ts := httptest.NewServer(gw)
+	t.Cleanup(func() {
+		gw.Close()
+		ts.Close()
+		conn.Close()
+		gs.Stop()
+		c.Close()
+	})
+	return ts
+}
+
+func get(t *testing.T, url, key string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "" {
+		req.Header.Set("X-Api-Key", key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestGetRate(t *testing.T) {
+	ts := newTestGateway(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		key    string
+		status int
+		code   codes.Code
+	}{
+		{"rate", "/rates/EUR/USD", "key", http.StatusOK, codes.OK},
+		{"lower case codes", "/rates/eur/gbp", "key", http.StatusOK, codes.OK},
+		{"unknown currency", "/rates/EUR/XYZ", "key", http.StatusBadRequest, codes.InvalidArgument},
+		{"missing key", "/rates/EUR/USD", "", http.StatusUnauthorized, codes.Unauthenticated},
+		{"unknown route", "/rates/EUR", "key", http.StatusNotFound, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, ts.URL+tt.path, tt.key)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if resp.StatusCode == http.StatusNotFound {
+				return
+			}
+
+			var body struct {
+				Rate    float64 `json:"rate"`
+				Code    int     `json:"code"`
+				Message string  `json:"message"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if codes.Code(body.Code) != tt.code {
+				t.Errorf("expected code %v, got %v (%s)", tt.code, codes.Code(body.Code), body.Message)
+			}
+			if tt.code == codes.OK && body.Rate <= 0 {
+				t.Errorf("expected a rate, got %v", body.Rate)
+			}
+			if resp.Header.Get(requestIDHeader) == "" && tt.code != codes.Unauthenticated {
+				t.Error("expected the request ID in the response headers")
+			}
+		})
+	}
+}
+
+func TestListCurrencies(t *testing.T) {
+	ts := newTestGateway(t)
+
+	resp := get(t, ts.URL+"/currencies", "key")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Currencies []string `json:"currencies"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Currencies) == 0 {
+		t.Error("expected the supported currencies")
+	}
+}
+
+func TestGetCandles(t *testing.T) {
+	ts := newTestGateway(t)
+
+	resp := get(t, ts.URL+"/rates/EUR/USD/candles?interval=1h", "key")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Interval string `json:"interval"`
+		Candles  []struct {
+			Open    float64 `json:"open"`
+			Samples int     `json:"samples"`
+		} `json:"candles"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Interval != "ONE_HOUR" || len(body.Candles) == 0 || body.Candles[0].Open <= 0 {
+		t.Errorf("expected hourly candles, got %+v", body)
+	}
+
+	for _, query := range []string{"?interval=2m", "?from=yesterday"} {
+		if resp := get(t, ts.URL+"/rates/EUR/USD/candles"+query, "key"); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+// event is a parsed Server-Sent Event
+type event struct {
+	name, id, data string
+}
+
+// readEvents reads n events from an event stream, comments are skipped
+func readEvents(t *testing.T, resp *http.Response, n int) []event {
+	t.Helper()
+
+	var events []event
+	var current event
+	scanner := bufio.NewScanner(resp.Body)
+	for len(events) < n && scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if current.name != "" {
+				events = append(events, current)
+			}
+			current = event{}
+		case strings.HasPrefix(line, "event: "):
+			current.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	if len(events) < n {
+		t.Fatalf("expected %d events, got %v (%v)", n, events, scanner.Err())
+	}
+	return events
+}
+
+func TestStreamRates(t *testing.T) {
+	ts := newTestGateway(t)
+
+	resp := get(t, ts.URL+"/rates/stream?pair=EUR/USD&pair=eur-gbp", "key")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %q", ct)
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range readEvents(t, resp, 2) {
+		if e.name != "rate" {
+			t.Fatalf("expected a rate event, got %+v", e)
+		}
+		if _, _, ok := parseEventID(e.id); !ok {
+			t.Errorf("expected a resumable event ID, got %q", e.id)
+		}
+		var rr struct {
+			DestinationCode string `json:"destinationCode"`
+		}
+		if err := json.Unmarshal([]byte(e.data), &rr); err != nil {
+			t.Fatal(err)
+		}
+		seen[rr.DestinationCode] = true
+	}
+	if !seen["USD"] || !seen["GBP"] {
+		t.Errorf("expected the rates of both pairs, got %v", seen)
+	}
+}
+
+func TestStreamRatesErrors(t *testing.T) {
+	ts := newTestGateway(t)
+
+	tests := []struct {
+		name   string
+		query  string
+		key    string
+		status int
+	}{
+		{"no pairs", "", "key", http.StatusBadRequest},
+		{"malformed pair", "?pair=EURUSD", "key", http.StatusBadRequest},
+		{"invalid option", "?pair=EUR/USD&min_interval=soon", "key", http.StatusBadRequest},
+		{"unknown currency", "?pair=EUR/XYZ", "key", http.StatusBadRequest},
+		{"missing key", "?pair=EUR/USD", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := get(t, ts.URL+"/rates/stream"+tt.query, tt.key)
+			if resp.StatusCode != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestStreamRatesResume(t *testing.T) {
+	ts := newTestGateway(t)
+
+	resp := get(t, ts.URL+"/rates/stream?pair=EUR/USD", "key")
+	last := readEvents(t, resp, 1)[0]
+	resp.Body.Close()
+	token, _, _ := parseEventID(last.id)
+
+	// the subscriptions can be resumed once the server noticed the closed stream,
+	// until then the gateway subscribes to the pairs of the query again
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resumed := stream(t, ts.URL+"/rates/stream?pair=EUR/USD", last.id)
+		if id, _, _ := parseEventID(readEvents(t, resumed, 1)[0].id); id == token {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the subscriptions to be resumed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// an unknown token falls back to the pairs of the query
+	resp = stream(t, ts.URL+"/rates/stream?pair=EUR/USD", "unknown:1")
+	if e := readEvents(t, resp, 1)[0]; e.name != "rate" || strings.HasPrefix(e.id, "unknown:") {
+		t.Errorf("expected a rate of a new subscription, got %+v", e)
+	}
+}
+
+// stream opens an event stream reconnecting with lastEventID
+func stream(t *testing.T, url, lastEventID string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Api-Key", "key")
+	req.Header.Set("Last-Event-ID", lastEventID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	return resp
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := map[codes.Code]int{
+		codes.OK:                http.StatusOK,
+		codes.InvalidArgument:   http.StatusBadRequest,
+		codes.OutOfRange:        http.StatusBadRequest,
+		codes.NotFound:          http.StatusNotFound,
+		codes.Unauthenticated:   http.StatusUnauthorized,
+		codes.PermissionDenied:  http.StatusForbidden,
+		codes.ResourceExhausted: http.StatusTooManyRequests,
+		codes.Unavailable:       http.StatusServiceUnavailable,
+		codes.Internal:          http.StatusInternalServerError,
+		codes.DataLoss:          http.StatusInternalServerError,
+	}
+	for code, expected := range tests {
+		if got := HTTPStatus(code); got != expected {
+			t.Errorf("%v: expected %d, got %d", code, expected, got)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamRates streams the rates of the pairs in the query as Server-Sent Events,
+// like SubscribeRates does over gRPC:
+//
+//	GET /rates/stream?pair=EUR/USD&pair=EUR/GBP&min_change_bps=5&min_interval=1s&max_silence=1m
+//
+// Every update is a "rate" event carrying a RateResponse, failed requests on the
+// stream are "error" events carrying a google.rpc.Status. The ID of an update
+// allows a reconnecting client to resume the subscriptions without missing updates.
+func (g *Gateway) streamRates(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		g.writeError(w, status.Error(codes.Internal, "streaming is not supported by the connection"))
+		return
+	}
+
+	req, err := subscriptionRequest(r)
+	if err != nil {
+		g.writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(outgoingContext(r))
+	defer cancel()
+
+	stream, err := g.client.SubscribeRates(ctx)
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+
+	// errors before the first event are returned as a plain HTTP response
+	ack, err := subscribe(stream, req, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		g.writeError(w, err)
+		return
+	}
+	if header, err := stream.Header(); err == nil {
+		setRequestID(w, header)
+	}
+	token := ack.GetResumeToken()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") // keep reverse proxies from buffering events
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	messages := make(chan *protos.StreamingRateResponse)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(g.opts.KeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-g.closeCh:
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+			err := stream.Send(&protos.SubscriptionRequest{
+				Operation: &protos.SubscriptionRequest_Heartbeat{Heartbeat: &protos.Heartbeat{}},
+			})
+			if err != nil {
+				return
+			}
+		case err := <-errCh:
+			// the server ended the stream, a closed client connection cancels ctx
+			if err != io.EOF && ctx.Err() == nil {
+				_ = g.writeEvent(w, "error", "", status.Convert(err).Proto())
+				flusher.Flush()
+			}
+			return
+		case msg := <-messages:
+			switch m := msg.GetMessage().(type) {
+			case *protos.StreamingRateResponse_RateResponse:
+				id := fmt.Sprintf("%s:%d", token, m.RateResponse.GetSequence())
+				if err := g.writeEvent(w, "rate", id, m.RateResponse); err != nil {
+					return
+				}
+			case *protos.StreamingRateResponse_Error:
+				if err := g.writeEvent(w, "error", "", m.Error); err != nil {
+					return
+				}
+			case *protos.StreamingRateResponse_Ack:
+				if t := m.Ack.GetResumeToken(); t != "" {
+					token = t
+				}
+				continue
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeEvent writes msg as an event of type event, id is omitted when empty
+func (g *Gateway) writeEvent(w http.ResponseWriter, event, id string, msg proto.Message) error {
+	body, err := g.marshal.Marshal(msg)
+	if err != nil {
+		g.log.Error("Unable to marshal event", "event", event, "error", err)
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", event)
+	if id != "" {
+		fmt.Fprintf(&b, "id: %s\n", id)
+	}
+	fmt.Fprintf(&b, "data: %s\n\n", body)
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// subscribe sends the first request of an event stream and returns its
+// acknowledgement. A client reconnecting with the ID of its last event resumes
+// the subscriptions of its previous stream, req is sent when that is not possible.
+func subscribe(stream protos.Currency_SubscribeRatesClient, req *protos.SubscriptionRequest, lastEventID string) (*protos.SubscriptionAck, error) {
+	if token, sequence, ok := parseEventID(lastEventID); ok {
+		ack, err := exchange(stream, &protos.SubscriptionRequest{
+			Operation: &protos.SubscriptionRequest_Resume{
+				Resume: &protos.Resume{Token: token, LastSequence: sequence},
+			},
+		})
+		if err == nil {
+			return ack, nil
+		}
+		if !errors.As(err, &errStreamRequest{}) {
+			return nil, err
+		}
+		// the subscriptions expired, subscribe to the requested pairs again
+	}
+	return exchange(stream, req)
+}
+
+// errStreamRequest wraps an error the server returned for a single request on
+// the stream, the stream itself is still usable
+type errStreamRequest struct {
+	err error
+}
+
+func (e errStreamRequest) Error() string { return e.err.Error() }
+func (e errStreamRequest) Unwrap() error { return e.err }
+
+// exchange sends req and waits for its acknowledgement
+func exchange(stream protos.Currency_SubscribeRatesClient, req *protos.SubscriptionRequest) (*protos.SubscriptionAck, error) {
+	if err := stream.Send(req); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// a stream rejected by the server fails Send with io.EOF, Recv returns the status
+	msg, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if e := msg.GetError(); e != nil {
+		return nil, errStreamRequest{status.ErrorProto(e)}
+	}
+	if msg.GetAck() == nil {
+		return nil, status.Error(codes.Internal, "expected an acknowledgement of the subscription")
+	}
+	return msg.GetAck(), nil
+}
+
+// subscriptionRequest creates the request replacing the subscriptions of a new
+// stream with the pairs and options in the query of r
+func subscriptionRequest(r *http.Request) (*protos.SubscriptionRequest, error) {
+	query := r.URL.Query()
+
+	pairs := query["pair"]
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("at least one pair is required")
+	}
+	replace := &protos.ReplaceSubscriptions{}
+	for _, p := range pairs {
+		rr, err := parsePair(p)
+		if err != nil {
+			return nil, err
+		}
+		replace.Pairs = append(replace.Pairs, rr)
+	}
+
+	opts := &protos.SubscriptionOptions{}
+	if v := query.Get("min_change_bps"); v != "" {
+		bps, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("min_change_bps: %w", err)
+		}
+		opts.MinChangeBps = uint32(bps)
+	}
+	for name, field := range map[string]**durationpb.Duration{
+		"min_interval": &opts.MinInterval,
+		"max_silence":  &opts.MaxSilence,
+	} {
+		if v := query.Get(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			*field = durationpb.New(d)
+		}
+	}
+
+	return &protos.SubscriptionRequest{
+		Options:   opts,
+		Operation: &protos.SubscriptionRequest_Replace{Replace: replace},
+	}, nil
+}
+
+// parseEventID splits the ID of a rate event into the resume token and sequence
+func parseEventID(id string) (string, uint64, bool) {
+	token, seq, ok := strings.Cut(id, ":")
+	if !ok || token == "" {
+		return "", 0, false
+	}
+	sequence, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return token, sequence, true
+}
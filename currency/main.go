@@ -3,47 +3,134 @@ package main
 import (
 	"context"
 	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/config"
 	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/gateway"
+	"github.com/kahvecikaan/buildingMicroservices/currency/metrics"
 	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
 	"github.com/kahvecikaan/buildingMicroservices/currency/server"
+	"github.com/kahvecikaan/buildingMicroservices/currency/tlsconfig"
+	"github.com/nicholasjackson/env"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 )
 
+// Environment variables, every setting of the config file can also be set
+// through its own environment variable, see the config package
+var (
+	configFile = env.String("CONFIG_FILE", false,
+		"", "Path of a JSON config file, settings from the environment take precedence")
+)
+
 func main() {
+	env.Parse()
+
+	// Load the configuration
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		hclog.Default().Error("Invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize Logger
 	log := hclog.New(&hclog.LoggerOptions{
 		Name:  "CurrencyService",
 		Color: hclog.AutoColor,
-		Level: hclog.LevelFromString("debug"),
+		Level: hclog.LevelFromString(cfg.LogLevel),
 	})
 
-	// Initialize ExchangeRates
-	rates, err := data.NewRates(log)
+	// Initialize the rate sources
+	sources, err := cfg.RateSources(log)
+	if err != nil {
+		log.Error("Unable to create rate provider", "error", err)
+		os.Exit(1)
+	}
+
+	// Initialize ExchangeRates, falling back to the snapshot if no source is available
+	rates, err := data.NewRatesFromSources(log, sources, cfg.RatesOptions())
 	if err != nil {
 		log.Error("Unable to generate rates", "error", err)
 		os.Exit(1)
 	}
 
+	// Initialize HistoricalRates, the service can run without them so failures are not fatal
+	history := data.NewHistoricalRates(log)
+	if err := history.LoadSource(cfg.History.Source); err != nil {
+		log.Error("Unable to load historical rates", "source", cfg.History.Source, "error", err)
+	}
+	history.RefreshEvery(cfg.History.Source, time.Duration(cfg.History.RefreshInterval))
+	defer history.Close()
+
 	// Create Currency server instance
-	currencyServer := server.NewCurrency(log, rates)
+	currencyServer := server.NewCurrency(log, rates, history, cfg.ServerOptions())
+
+	// Create the health server, it reports whether the rates are fit to serve
+	healthServer := server.NewHealth(log, rates, cfg.HealthOptions())
+
+	// Create the Prometheus metrics of the service
+	m := metrics.New(rates, currencyServer)
 
-	// Create a new gRPC server
-	gs := grpc.NewServer()
+	// Create a new gRPC server, every RPC gets a request ID, is logged and timed,
+	// panics in handlers are returned to the client as internal errors
+	unary := []grpc.UnaryServerInterceptor{server.UnaryLogging(log), m.UnaryInterceptor()}
+	stream := []grpc.StreamServerInterceptor{server.StreamLogging(log), m.StreamInterceptor()}
 
-	// Register the Currency server with the gRPC server
+	// Authenticate clients and apply their quotas when a credentials file is configured
+	var auth *server.Authenticator
+	if cfg.Auth.CredentialsFile != "" {
+		creds, err := server.LoadCredentials(cfg.Auth.CredentialsFile)
+		if err != nil {
+			log.Error("Unable to load client credentials", "error", err)
+			os.Exit(1)
+		}
+		auth = server.NewAuthenticator(log, creds)
+		unary = append(unary, auth.UnaryInterceptor())
+		stream = append(stream, auth.StreamInterceptor())
+	} else {
+		log.Warn("Client authentication is disabled, no credentials file is configured")
+	}
+
+	interceptors := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(append(unary, server.UnaryRecovery(log))...),
+		grpc.ChainStreamInterceptor(append(stream, server.StreamRecovery(log))...),
+	}
+	serverOpts := interceptors
+
+	// Serve TLS when a certificate is configured, rotated files are reloaded
+	if cfg.TLS.Enabled() {
+		certs, err := tlsconfig.NewReloader(log, cfg.TLSOptions())
+		if err != nil {
+			log.Error("Unable to load TLS certificates", "error", err)
+			os.Exit(1)
+		}
+		defer certs.Close()
+
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(certs.ServerConfig())))
+		log.Info("TLS enabled", "client_certificates", cfg.TLS.ClientCAFile != "",
+			"require_client_cert", cfg.TLS.RequireClientCert)
+	}
+
+	gs := grpc.NewServer(serverOpts...)
+
+	// Register the Currency and health servers with the gRPC server
 	protos.RegisterCurrencyServer(gs, currencyServer)
+	// the Admin service only accepts clients marked as admin in the credentials file
+	protos.RegisterAdminServer(gs, server.NewAdmin(log, rates))
+	healthpb.RegisterHealthServer(gs, healthServer)
 
 	// Register the reflection service for debugging and introspection
 	reflection.Register(gs)
 
-	// Create a TCP listener on port 9092
-	lis, err := net.Listen("tcp", ":9092")
+	// Create a TCP listener on the configured address
+	lis, err := net.Listen("tcp", cfg.BindAddress)
 	if err != nil {
 		log.Error("Unable to create listener", "error", err)
 		os.Exit(1)
@@ -51,20 +138,78 @@ func main() {
 
 	// Start the gRPC server in a separate goroutine
 	go func() {
-		log.Info("Currency gRPC server is running on port :9092")
+		log.Info("Currency gRPC server is running", "address", cfg.BindAddress)
 		if err := gs.Serve(lis); err != nil && err != grpc.ErrServerStopped {
 			log.Error("Failed to serve gRPC server", "error", err)
 			os.Exit(1)
 		}
 	}()
 
+	// Serve the metrics over HTTP next to the gRPC listener
+	var metricsServer *http.Server
+	if cfg.MetricsAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", m.Handler())
+		metricsServer = &http.Server{
+			Addr:              cfg.MetricsAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+
+		go func() {
+			log.Info("Metrics are served", "address", cfg.MetricsAddress)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error("Failed to serve metrics", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
+	// Serve the HTTP/JSON gateway, it calls the Currency server through an in-process
+	// gRPC server with the same interceptors, so its requests are authenticated and
+	// limited like those of gRPC clients
+	var gatewayServer *http.Server
+	var inProcess *grpc.Server
+	if cfg.GatewayAddress != "" {
+		inProcess = grpc.NewServer(interceptors...)
+		protos.RegisterCurrencyServer(inProcess, currencyServer)
+
+		conn, err := gateway.ServeInProcess(log, inProcess)
+		if err != nil {
+			log.Error("Unable to connect the gateway", "error", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+
+		gw := gateway.New(log.Named("gateway"), protos.NewCurrencyClient(conn), gateway.DefaultOptions())
+		gatewayServer = &http.Server{
+			Addr:              cfg.GatewayAddress,
+			Handler:           gw,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		// event streams only end when the gateway is closed
+		gatewayServer.RegisterOnShutdown(gw.Close)
+
+		go func() {
+			log.Info("HTTP/JSON gateway is running", "address", cfg.GatewayAddress)
+			if err := gatewayServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Error("Failed to serve the gateway", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	// Channel to listen for OS signals
 	sigChan := make(chan os.Signal, 1)
-	// Notify on Interrupt and Terminate signals
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	// Notify on Interrupt and Terminate signals, and on Hangup to reload the configuration
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
-	// Block until a signal is received
+	// Block until a shutdown signal is received
 	sig := <-sigChan
+	for sig == syscall.SIGHUP {
+		cfg = reloadConfig(log, rates, auth, cfg)
+		sig = <-sigChan
+	}
 	log.Info("Received signal, initiating graceful shutdown", "signal", sig)
 
 	// Create a deadline to wait for
@@ -76,9 +221,23 @@ func main() {
 
 	// Start a goroutine to perform the shutdown
 	go func() {
+		// Report NOT_SERVING so clients stop sending new requests
+		healthServer.Close()
+
 		// Stop accepting new connections and gracefully shutdown gRPC server
 		gs.GracefulStop()
 
+		// Stop the gateway and the in-process server behind it
+		if gatewayServer != nil {
+			_ = gatewayServer.Shutdown(ctx)
+			inProcess.GracefulStop()
+		}
+
+		// Stop serving metrics
+		if metricsServer != nil {
+			_ = metricsServer.Shutdown(ctx)
+		}
+
 		// Call Close on Currency server to terminate goroutines and release resources
 		currencyServer.Close()
 
@@ -96,3 +255,49 @@ func main() {
 
 	log.Info("Shutdown complete")
 }
+
+// reloadConfig loads the configuration again and applies the settings that can
+// change while running, the log level, the rate monitor interval and volatility and
+// the client credentials. Existing streams are not affected. It returns the
+// configuration now in effect.
+func reloadConfig(log hclog.Logger, rates *data.ExchangeRates, auth *server.Authenticator, current *config.Config) *config.Config {
+	log.Info("Reloading configuration")
+
+	next, err := config.Load(*configFile)
+	if err != nil {
+		log.Error("Invalid configuration, keeping the current one", "error", err)
+		return current
+	}
+
+	// the credentials file is read from its current path, a new path needs a restart
+	var creds *server.Credentials
+	if auth != nil {
+		creds, err = server.LoadCredentials(current.Auth.CredentialsFile)
+		if err != nil {
+			log.Error("Invalid client credentials, keeping the current configuration", "error", err)
+			return current
+		}
+	}
+
+	log.SetLevel(hclog.LevelFromString(next.LogLevel))
+	if creds != nil {
+		auth.Reload(creds)
+	}
+	rates.Reconfigure(next.MonitorOptions())
+
+	if changed := next.RestartRequired(current); len(changed) > 0 {
+		log.Warn("Some settings only take effect after a restart", "settings", changed)
+	}
+
+	// only the reloadable settings are in effect until the next restart
+	applied := *current
+	applied.LogLevel = next.LogLevel
+	applied.Rates.MonitorInterval = next.Rates.MonitorInterval
+	applied.Rates.Volatility = next.Rates.Volatility
+
+	log.Info("Configuration reloaded",
+		"log_level", applied.LogLevel,
+		"monitor_interval", time.Duration(applied.Rates.MonitorInterval),
+		"volatility", applied.Rates.Volatility)
+	return &applied
+}
@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"context"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"time"
+)
+
+const namespace = "currency"
+
+var (
+	streamsDesc = prometheus.NewDesc(
+		namespace+"_streams_active",
+		"Number of open SubscribeRates streams.",
+		nil, nil)
+	pairSubscriptionsDesc = prometheus.NewDesc(
+		namespace+"_pair_subscriptions",
+		"Number of streams subscribed to a currency pair.",
+		[]string{"base", "destination"}, nil)
+	updatesSentDesc = prometheus.NewDesc(
+		namespace+"_rate_updates_sent_total",
+		"Rate updates written to subscriber streams.",
+		nil, nil)
+	sendFailuresDesc = prometheus.NewDesc(
+		namespace+"_rate_update_send_failures_total",
+		"Rate updates that could not be written to a subscriber stream.",
+		nil, nil)
+	updatesDroppedDesc = prometheus.NewDesc(
+		namespace+"_rate_updates_dropped_total",
+		"Rate updates discarded because a subscriber fell behind.",
+		nil, nil)
+	updatesCoalescedDesc = prometheus.NewDesc(
+		namespace+"_rate_updates_coalesced_total",
+		"Rate updates replaced by a newer value before a slow subscriber received them.",
+		nil, nil)
+	staleRemovedDesc = prometheus.NewDesc(
+		namespace+"_stale_subscriptions_removed_total",
+		"Subscriptions removed because the client was inactive.",
+		nil, nil)
+	rateAgeDesc = prometheus.NewDesc(
+		namespace+"_rates_age_seconds",
+		"Time since the rates were fetched from the provider, simulated ticks do not reset it.",
+		nil, nil)
+	ratesStaleDesc = prometheus.NewDesc(
+		namespace+"_rates_stale",
+		"1 while the rates are served from a snapshot or older than the maximum age, 0 otherwise.",
+		nil, nil)
+	providerFetchesDesc = prometheus.NewDesc(
+		namespace+"_provider_fetches_total",
+		"Rate set fetches from the provider by result.",
+		[]string{"result"}, nil)
+	ratesRejectedDesc = prometheus.NewDesc(
+		namespace+"_rates_rejected_total",
+		"Fetched rates rejected by the anomaly filter.",
+		nil, nil)
+)
+
+// Metrics exposes the state of the currency service in the Prometheus format
+type Metrics struct {
+	registry    *prometheus.Registry
+	rpcDuration *prometheus.HistogramVec
+	since       func(time.Time) time.Duration // time.Since, fixed in tests
+}
+
+// New creates the metrics of rates and the Currency server c, the values are
+// read from them whenever the metrics are scraped
+func New(rates *data.ExchangeRates, c *server.Currency) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of gRPC calls by method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "code"}),
+		since: time.Since,
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rpcDuration,
+		&stateCollector{rates: rates, currency: c},
+	)
+	return m
+}
+
+// Handler serves the metrics for scraping
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// UnaryInterceptor records the duration of every unary RPC
+func (m *Metrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+// StreamInterceptor records the duration of every streaming RPC
+func (m *Metrics) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, start, err)
+		return err
+	}
+}
+
+func (m *Metrics) observe(method string, start time.Time, err error) {
+	m.rpcDuration.WithLabelValues(method, status.Code(err).String()).Observe(m.since(start).Seconds())
+}
+
+// stateCollector reads the current state of the rates and the server on every scrape
+type stateCollector struct {
+	rates    *data.ExchangeRates
+	currency *server.Currency
+}
+
+func (s *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		streamsDesc, pairSubscriptionsDesc, updatesSentDesc, sendFailuresDesc, updatesDroppedDesc,
+		updatesCoalescedDesc, staleRemovedDesc, rateAgeDesc, ratesStaleDesc, providerFetchesDesc,
+		ratesRejectedDesc,
+	} {
+		ch <- d
+	}
+}
+
+func (s *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	streams, pairs := s.currency.SubscriptionStats()
+	ch <- prometheus.MustNewConstMetric(streamsDesc, prometheus.GaugeValue, float64(streams))
+	for pair, n := range pairs {
+		ch <- prometheus.MustNewConstMetric(pairSubscriptionsDesc, prometheus.GaugeValue, float64(n), pair.Base, pair.Dest)
+	}
+
+	stats := s.currency.StreamStats()
+	ch <- prometheus.MustNewConstMetric(updatesSentDesc, prometheus.CounterValue, float64(stats.Sent))
+	ch <- prometheus.MustNewConstMetric(sendFailuresDesc, prometheus.CounterValue, float64(stats.SendFailures))
+	ch <- prometheus.MustNewConstMetric(updatesDroppedDesc, prometheus.CounterValue, float64(stats.Dropped))
+	ch <- prometheus.MustNewConstMetric(updatesCoalescedDesc, prometheus.CounterValue, float64(stats.Coalesced))
+	ch <- prometheus.MustNewConstMetric(staleRemovedDesc, prometheus.CounterValue, float64(stats.StaleRemoved))
+
+	ch <- prometheus.MustNewConstMetric(rateAgeDesc, prometheus.GaugeValue, time.Since(s.rates.UpdatedAt()).Seconds())
+	stale := 0.0
+	if s.rates.Stale() {
+		stale = 1
+	}
+	ch <- prometheus.MustNewConstMetric(ratesStaleDesc, prometheus.GaugeValue, stale)
+
+	fetches := s.rates.FetchStats()
+	ch <- prometheus.MustNewConstMetric(providerFetchesDesc, prometheus.CounterValue, float64(fetches.Successes), "success")
+	ch <- prometheus.MustNewConstMetric(providerFetchesDesc, prometheus.CounterValue, float64(fetches.Failures), "failure")
+	ch <- prometheus.MustNewConstMetric(ratesRejectedDesc, prometheus.CounterValue, float64(s.rates.Anomalies()))
+}
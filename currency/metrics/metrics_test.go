@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/server"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestMetrics creates the metrics of a Currency server with the static rates,
+// the server is stopped when the test ends
+func newTestMetrics(t *testing.T) (*Metrics, *stateCollector) {
+	t.Helper()
+
+	opts := data.DefaultRatesOptions()
+	opts.Monitor.Interval = time.Hour
+	rates, err := data.NewRates(hclog.NewNullLogger(), data.NewStaticProvider(data.DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := server.NewCurrency(hclog.NewNullLogger(), rates, data.NewHistoricalRates(hclog.NewNullLogger()), server.DefaultOptions())
+	t.Cleanup(c.Close)
+
+	return New(rates, c), &stateCollector{rates: rates, currency: c}
+}
+
+func TestStateMetrics(t *testing.T) {
+	_, collector := newTestMetrics(t)
+
+	expected := `
+	# HELP currency_provider_fetches_total Rate set fetches from the provider by result.
+	# TYPE currency_provider_fetches_total counter
+	currency_provider_fetches_total{result="failure"} 0
+	currency_provider_fetches_total{result="success"} 1
+	# HELP currency_rates_rejected_total Fetched rates rejected by the anomaly filter.
+	# TYPE currency_rates_rejected_total counter
+	currency_rates_rejected_total 0
+	# HELP currency_rates_stale 1 while the rates are served from a snapshot or older than the maximum age, 0 otherwise.
+	# TYPE currency_rates_stale gauge
+	currency_rates_stale 0
+	# HELP currency_streams_active Number of open SubscribeRates streams.
+	# TYPE currency_streams_active gauge
+	currency_streams_active 0
+	# HELP currency_rate_updates_sent_total Rate updates written to subscriber streams.
+	# TYPE currency_rate_updates_sent_total counter
+	currency_rate_updates_sent_total 0
+	`
+	// the rate age depends on the time of the scrape and is left out
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"currency_provider_fetches_total", "currency_rates_rejected_total", "currency_rates_stale",
+		"currency_streams_active", "currency_rate_updates_sent_total", "currency_pair_subscriptions")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRPCDurationByCode(t *testing.T) {
+	m, _ := newTestMetrics(t)
+	m.since = func(time.Time) time.Duration { return 20 * time.Millisecond }
+
+	_, err := m.UnaryInterceptor()(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/currency.Currency/GetRate"},
+		func(context.Context, any) (any, error) { return nil, status.Error(codes.NotFound, "rate not found") })
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected the error of the handler, got %v", err)
+	}
+	err = m.StreamInterceptor()(nil, nil,
+		&grpc.StreamServerInfo{FullMethod: "/currency.Currency/SubscribeRates"},
+		func(any, grpc.ServerStream) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+	# HELP currency_rpc_duration_seconds Duration of gRPC calls by method and status code.
+	# TYPE currency_rpc_duration_seconds histogram
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.005"} 0
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.01"} 0
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.025"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.05"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.1"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.25"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="0.5"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="1"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="2.5"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="5"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="10"} 1
+	currency_rpc_duration_seconds_bucket{code="NotFound",method="/currency.Currency/GetRate",le="+Inf"} 1
+	currency_rpc_duration_seconds_sum{code="NotFound",method="/currency.Currency/GetRate"} 0.02
+	currency_rpc_duration_seconds_count{code="NotFound",method="/currency.Currency/GetRate"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.005"} 0
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.01"} 0
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.025"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.05"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.1"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.25"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="0.5"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="1"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="2.5"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="5"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="10"} 1
+	currency_rpc_duration_seconds_bucket{code="OK",method="/currency.Currency/SubscribeRates",le="+Inf"} 1
+	currency_rpc_duration_seconds_sum{code="OK",method="/currency.Currency/SubscribeRates"} 0.02
+	currency_rpc_duration_seconds_count{code="OK",method="/currency.Currency/SubscribeRates"} 1
+	`
+	if err := testutil.CollectAndCompare(m.rpcDuration, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
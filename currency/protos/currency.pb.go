@@ -10,6 +10,8 @@ import (
 	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -21,7 +23,171 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// Currencies is an enum which represents the allowed currencies for the API
+// SubscriptionOperation identifies the operation a SubscriptionAck acknowledges
+type SubscriptionOperation int32
+
+const (
+	SubscriptionOperation_SUBSCRIBE   SubscriptionOperation = 0
+	SubscriptionOperation_UNSUBSCRIBE SubscriptionOperation = 1
+	SubscriptionOperation_REPLACE     SubscriptionOperation = 2
+	SubscriptionOperation_HEARTBEAT   SubscriptionOperation = 3
+	SubscriptionOperation_RESUME      SubscriptionOperation = 4
+)
+
+// Enum value maps for SubscriptionOperation.
+var (
+	SubscriptionOperation_name = map[int32]string{
+		0: "SUBSCRIBE",
+		1: "UNSUBSCRIBE",
+		2: "REPLACE",
+		3: "HEARTBEAT",
+		4: "RESUME",
+	}
+	SubscriptionOperation_value = map[string]int32{
+		"SUBSCRIBE":   0,
+		"UNSUBSCRIBE": 1,
+		"REPLACE":     2,
+		"HEARTBEAT":   3,
+		"RESUME":      4,
+	}
+)
+
+func (x SubscriptionOperation) Enum() *SubscriptionOperation {
+	p := new(SubscriptionOperation)
+	*p = x
+	return p
+}
+
+func (x SubscriptionOperation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscriptionOperation) Descriptor() protoreflect.EnumDescriptor {
+	return file_currency_proto_enumTypes[0].Descriptor()
+}
+
+func (SubscriptionOperation) Type() protoreflect.EnumType {
+	return &file_currency_proto_enumTypes[0]
+}
+
+func (x SubscriptionOperation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscriptionOperation.Descriptor instead.
+func (SubscriptionOperation) EnumDescriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{0}
+}
+
+// CandleInterval is the length of the buckets of a GetCandles call
+type CandleInterval int32
+
+const (
+	// ONE_MINUTE buckets start at every full minute
+	CandleInterval_ONE_MINUTE CandleInterval = 0
+	// FIVE_MINUTES buckets start at every multiple of five minutes
+	CandleInterval_FIVE_MINUTES CandleInterval = 1
+	// ONE_HOUR buckets start at every full hour
+	CandleInterval_ONE_HOUR CandleInterval = 2
+)
+
+// Enum value maps for CandleInterval.
+var (
+	CandleInterval_name = map[int32]string{
+		0: "ONE_MINUTE",
+		1: "FIVE_MINUTES",
+		2: "ONE_HOUR",
+	}
+	CandleInterval_value = map[string]int32{
+		"ONE_MINUTE":   0,
+		"FIVE_MINUTES": 1,
+		"ONE_HOUR":     2,
+	}
+)
+
+func (x CandleInterval) Enum() *CandleInterval {
+	p := new(CandleInterval)
+	*p = x
+	return p
+}
+
+func (x CandleInterval) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CandleInterval) Descriptor() protoreflect.EnumDescriptor {
+	return file_currency_proto_enumTypes[1].Descriptor()
+}
+
+func (CandleInterval) Type() protoreflect.EnumType {
+	return &file_currency_proto_enumTypes[1]
+}
+
+func (x CandleInterval) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CandleInterval.Descriptor instead.
+func (CandleInterval) EnumDescriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{1}
+}
+
+// RoundingMode is an enum which represents how amounts are rounded to minor units
+type RoundingMode int32
+
+const (
+	// HALF_EVEN rounds to the nearest minor unit, ties to the even neighbour
+	RoundingMode_HALF_EVEN RoundingMode = 0
+	// HALF_UP rounds to the nearest minor unit, ties away from zero
+	RoundingMode_HALF_UP RoundingMode = 1
+	// TRUNCATE drops any fraction of a minor unit
+	RoundingMode_TRUNCATE RoundingMode = 2
+)
+
+// Enum value maps for RoundingMode.
+var (
+	RoundingMode_name = map[int32]string{
+		0: "HALF_EVEN",
+		1: "HALF_UP",
+		2: "TRUNCATE",
+	}
+	RoundingMode_value = map[string]int32{
+		"HALF_EVEN": 0,
+		"HALF_UP":   1,
+		"TRUNCATE":  2,
+	}
+)
+
+func (x RoundingMode) Enum() *RoundingMode {
+	p := new(RoundingMode)
+	*p = x
+	return p
+}
+
+func (x RoundingMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoundingMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_currency_proto_enumTypes[2].Descriptor()
+}
+
+func (RoundingMode) Type() protoreflect.EnumType {
+	return &file_currency_proto_enumTypes[2]
+}
+
+func (x RoundingMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoundingMode.Descriptor instead.
+func (RoundingMode) EnumDescriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{2}
+}
+
+// Currencies is an enum which represents the allowed currencies for the API.
+// It is kept for older clients, new clients should use the string code fields
+// which accept every currency published by the rate provider.
 type Currencies int32
 
 const (
@@ -148,11 +314,11 @@ func (x Currencies) String() string {
 }
 
 func (Currencies) Descriptor() protoreflect.EnumDescriptor {
-	return file_currency_proto_enumTypes[0].Descriptor()
+	return file_currency_proto_enumTypes[3].Descriptor()
 }
 
 func (Currencies) Type() protoreflect.EnumType {
-	return &file_currency_proto_enumTypes[0]
+	return &file_currency_proto_enumTypes[3]
 }
 
 func (x Currencies) Number() protoreflect.EnumNumber {
@@ -161,10 +327,12 @@ func (x Currencies) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Currencies.Descriptor instead.
 func (Currencies) EnumDescriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{0}
+	return file_currency_proto_rawDescGZIP(), []int{3}
 }
 
-// RateRequest defines the request for a GetRate call
+// RateRequest defines the request for a GetRate call. Currencies can be given either
+// with the Currencies enum or as ISO 4217 codes, the codes take precedence when set
+// and allow any currency the server has rates for.
 type RateRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -174,6 +342,10 @@ type RateRequest struct {
 	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
 	// Destination is the destination currency code for the rate
 	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency, e.g. "EUR"
+	BaseCode string `protobuf:"bytes,3,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,4,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
 }
 
 func (x *RateRequest) Reset() {
@@ -220,9 +392,24 @@ func (x *RateRequest) GetDestination() Currencies {
 	return Currencies_UNKNOWN
 }
 
+func (x *RateRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RateRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
 // RateResponse is the response from a GetRate call, it contains base, destination, and
-// rate which is a floating point number and can be used to convert between the
-// two currencies specified in the request.
+// rate which can be used to convert between the two currencies specified in the request.
+// Rate is a floating point approximation kept for compatibility, ExactRate carries the
+// same value as a decimal string without binary rounding error.
 type RateResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -234,6 +421,30 @@ type RateResponse struct {
 	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
 	// Rate is the returned currency rate
 	Rate float64 `protobuf:"fixed64,3,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// ExactRate is the returned currency rate as a decimal string, e.g. "1.0892"
+	ExactRate string `protobuf:"bytes,4,opt,name=ExactRate,proto3" json:"ExactRate,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,5,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,6,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Sequence identifies the snapshot of rates the rate was computed from, it
+	// increases every time the rates change so a lower value is an older rate
+	Sequence uint64 `protobuf:"varint,7,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+	// AsOf is the time the rates of the snapshot were published by the provider,
+	// or generated when the rates are simulated
+	AsOf *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=AsOf,proto3" json:"AsOf,omitempty"`
+	// EmittedAt is the time the server sent the rate
+	EmittedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=EmittedAt,proto3" json:"EmittedAt,omitempty"`
+	// Path lists the currencies the rate was converted through, from the base to
+	// the destination, e.g. ["GBP", "EUR", "USD"]
+	Path []string `protobuf:"bytes,10,rep,name=Path,proto3" json:"Path,omitempty"`
+	// Sources are the names of the rate sources quoting the pairs along Path
+	Sources []string `protobuf:"bytes,11,rep,name=Sources,proto3" json:"Sources,omitempty"`
+	// Stale is set when the rates are served from a snapshot, or were not
+	// refreshed from the providers within the maximum age
+	Stale bool `protobuf:"varint,12,opt,name=Stale,proto3" json:"Stale,omitempty"`
+	// Overridden is set when the rate was pinned by an operator, see Admin
+	Overridden bool `protobuf:"varint,13,opt,name=Overridden,proto3" json:"Overridden,omitempty"`
 }
 
 func (x *RateResponse) Reset() {
@@ -287,32 +498,121 @@ func (x *RateResponse) GetRate() float64 {
 	return 0
 }
 
-type StreamingRateResponse struct {
+func (x *RateResponse) GetExactRate() string {
+	if x != nil {
+		return x.ExactRate
+	}
+	return ""
+}
+
+func (x *RateResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RateResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *RateResponse) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RateResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+func (x *RateResponse) GetEmittedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EmittedAt
+	}
+	return nil
+}
+
+func (x *RateResponse) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *RateResponse) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *RateResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *RateResponse) GetOverridden() bool {
+	if x != nil {
+		return x.Overridden
+	}
+	return false
+}
+
+// SubscriptionRequest is a message sent by the client on a SubscribeRates stream.
+// Fields 1 to 4 match RateRequest so clients which still send a RateRequest keep
+// working, such a message subscribes to the pair or is a heartbeat when no currency
+// is set. New clients should set exactly one operation.
+type SubscriptionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Types that are assignable to Message:
-	//
-	//	*StreamingRateResponse_RateResponse
-	//	*StreamingRateResponse_Error
-	Message isStreamingRateResponse_Message `protobuf_oneof:"message"`
+	// Base is the base currency of a legacy subscribe request
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the destination currency of a legacy subscribe request
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// BaseCode is the ISO 4217 base currency code of a legacy subscribe request
+	BaseCode string `protobuf:"bytes,3,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 destination currency code of a legacy subscribe request
+	DestinationCode string `protobuf:"bytes,4,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// RequestId is chosen by the client and echoed in the acknowledgement
+	RequestId uint64 `protobuf:"varint,5,opt,name=RequestId,proto3" json:"RequestId,omitempty"`
+	// Options control how often updates are pushed for the pairs of a subscribe,
+	// replace or legacy request, they are ignored by the other operations
+	Options *SubscriptionOptions `protobuf:"bytes,6,opt,name=Options,proto3" json:"Options,omitempty"`
+	// Types that are assignable to Operation:
+	//	*SubscriptionRequest_Subscribe
+	//	*SubscriptionRequest_Unsubscribe
+	//	*SubscriptionRequest_Replace
+	//	*SubscriptionRequest_Heartbeat
+	//	*SubscriptionRequest_Resume
+	Operation isSubscriptionRequest_Operation `protobuf_oneof:"operation"`
 }
 
-func (x *StreamingRateResponse) Reset() {
-	*x = StreamingRateResponse{}
+func (x *SubscriptionRequest) Reset() {
+	*x = SubscriptionRequest{}
 	mi := &file_currency_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamingRateResponse) String() string {
+func (x *SubscriptionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamingRateResponse) ProtoMessage() {}
+func (*SubscriptionRequest) ProtoMessage() {}
 
-func (x *StreamingRateResponse) ProtoReflect() protoreflect.Message {
+func (x *SubscriptionRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_currency_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -324,68 +624,166 @@ func (x *StreamingRateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamingRateResponse.ProtoReflect.Descriptor instead.
-func (*StreamingRateResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*SubscriptionRequest) Descriptor() ([]byte, []int) {
 	return file_currency_proto_rawDescGZIP(), []int{2}
 }
 
-func (m *StreamingRateResponse) GetMessage() isStreamingRateResponse_Message {
+func (x *SubscriptionRequest) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *SubscriptionRequest) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *SubscriptionRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *SubscriptionRequest) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *SubscriptionRequest) GetOptions() *SubscriptionOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (m *SubscriptionRequest) GetOperation() isSubscriptionRequest_Operation {
 	if m != nil {
-		return m.Message
+		return m.Operation
 	}
 	return nil
 }
 
-func (x *StreamingRateResponse) GetRateResponse() *RateResponse {
-	if x, ok := x.GetMessage().(*StreamingRateResponse_RateResponse); ok {
-		return x.RateResponse
+func (x *SubscriptionRequest) GetSubscribe() *RateRequest {
+	if x, ok := x.GetOperation().(*SubscriptionRequest_Subscribe); ok {
+		return x.Subscribe
 	}
 	return nil
 }
 
-func (x *StreamingRateResponse) GetError() *status.Status {
-	if x, ok := x.GetMessage().(*StreamingRateResponse_Error); ok {
-		return x.Error
+func (x *SubscriptionRequest) GetUnsubscribe() *RateRequest {
+	if x, ok := x.GetOperation().(*SubscriptionRequest_Unsubscribe); ok {
+		return x.Unsubscribe
 	}
 	return nil
 }
 
-type isStreamingRateResponse_Message interface {
-	isStreamingRateResponse_Message()
+func (x *SubscriptionRequest) GetReplace() *ReplaceSubscriptions {
+	if x, ok := x.GetOperation().(*SubscriptionRequest_Replace); ok {
+		return x.Replace
+	}
+	return nil
 }
 
-type StreamingRateResponse_RateResponse struct {
-	RateResponse *RateResponse `protobuf:"bytes,1,opt,name=rate_response,json=rateResponse,proto3,oneof"`
+func (x *SubscriptionRequest) GetHeartbeat() *Heartbeat {
+	if x, ok := x.GetOperation().(*SubscriptionRequest_Heartbeat); ok {
+		return x.Heartbeat
+	}
+	return nil
 }
 
-type StreamingRateResponse_Error struct {
-	Error *status.Status `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+func (x *SubscriptionRequest) GetResume() *Resume {
+	if x, ok := x.GetOperation().(*SubscriptionRequest_Resume); ok {
+		return x.Resume
+	}
+	return nil
 }
 
-func (*StreamingRateResponse_RateResponse) isStreamingRateResponse_Message() {}
+type isSubscriptionRequest_Operation interface {
+	isSubscriptionRequest_Operation()
+}
 
-func (*StreamingRateResponse_Error) isStreamingRateResponse_Message() {}
+type SubscriptionRequest_Subscribe struct {
+	// subscribe adds a pair to the subscriptions of the stream
+	Subscribe *RateRequest `protobuf:"bytes,10,opt,name=subscribe,proto3,oneof"`
+}
 
-type Empty struct {
+type SubscriptionRequest_Unsubscribe struct {
+	// unsubscribe removes a pair from the subscriptions of the stream
+	Unsubscribe *RateRequest `protobuf:"bytes,11,opt,name=unsubscribe,proto3,oneof"`
+}
+
+type SubscriptionRequest_Replace struct {
+	// replace replaces all subscriptions of the stream with the given pairs
+	Replace *ReplaceSubscriptions `protobuf:"bytes,12,opt,name=replace,proto3,oneof"`
+}
+
+type SubscriptionRequest_Heartbeat struct {
+	// heartbeat keeps an idle stream from being removed as stale
+	Heartbeat *Heartbeat `protobuf:"bytes,13,opt,name=heartbeat,proto3,oneof"`
+}
+
+type SubscriptionRequest_Resume struct {
+	// resume restores the subscriptions of a previous stream and replays the
+	// updates it missed
+	Resume *Resume `protobuf:"bytes,14,opt,name=resume,proto3,oneof"`
+}
+
+func (*SubscriptionRequest_Subscribe) isSubscriptionRequest_Operation() {}
+
+func (*SubscriptionRequest_Unsubscribe) isSubscriptionRequest_Operation() {}
+
+func (*SubscriptionRequest_Replace) isSubscriptionRequest_Operation() {}
+
+func (*SubscriptionRequest_Heartbeat) isSubscriptionRequest_Operation() {}
+
+func (*SubscriptionRequest_Resume) isSubscriptionRequest_Operation() {}
+
+// SubscriptionOptions limit the updates pushed for a subscribed pair. Without options
+// every rate update is pushed.
+type SubscriptionOptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// MinChangeBps is the minimum relative change, in basis points, from the last rate
+	// pushed for the pair that causes a new push
+	MinChangeBps uint32 `protobuf:"varint,1,opt,name=MinChangeBps,proto3" json:"MinChangeBps,omitempty"`
+	// MinInterval is the minimum time between two pushes for the pair
+	MinInterval *durationpb.Duration `protobuf:"bytes,2,opt,name=MinInterval,proto3" json:"MinInterval,omitempty"`
+	// MaxSilence pushes the current rate if nothing was pushed for the pair for this
+	// long, even when MinChangeBps was not reached
+	MaxSilence *durationpb.Duration `protobuf:"bytes,3,opt,name=MaxSilence,proto3" json:"MaxSilence,omitempty"`
 }
 
-func (x *Empty) Reset() {
-	*x = Empty{}
+func (x *SubscriptionOptions) Reset() {
+	*x = SubscriptionOptions{}
 	mi := &file_currency_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Empty) String() string {
+func (x *SubscriptionOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Empty) ProtoMessage() {}
+func (*SubscriptionOptions) ProtoMessage() {}
 
-func (x *Empty) ProtoReflect() protoreflect.Message {
+func (x *SubscriptionOptions) ProtoReflect() protoreflect.Message {
 	mi := &file_currency_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -397,33 +795,55 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
-func (*Empty) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubscriptionOptions.ProtoReflect.Descriptor instead.
+func (*SubscriptionOptions) Descriptor() ([]byte, []int) {
 	return file_currency_proto_rawDescGZIP(), []int{3}
 }
 
-type ListCurrenciesResponse struct {
+func (x *SubscriptionOptions) GetMinChangeBps() uint32 {
+	if x != nil {
+		return x.MinChangeBps
+	}
+	return 0
+}
+
+func (x *SubscriptionOptions) GetMinInterval() *durationpb.Duration {
+	if x != nil {
+		return x.MinInterval
+	}
+	return nil
+}
+
+func (x *SubscriptionOptions) GetMaxSilence() *durationpb.Duration {
+	if x != nil {
+		return x.MaxSilence
+	}
+	return nil
+}
+
+// ReplaceSubscriptions is the set of pairs that replaces a stream's subscriptions
+type ReplaceSubscriptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Currencies []string `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	Pairs []*RateRequest `protobuf:"bytes,1,rep,name=Pairs,proto3" json:"Pairs,omitempty"`
 }
 
-func (x *ListCurrenciesResponse) Reset() {
-	*x = ListCurrenciesResponse{}
+func (x *ReplaceSubscriptions) Reset() {
+	*x = ReplaceSubscriptions{}
 	mi := &file_currency_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCurrenciesResponse) String() string {
+func (x *ReplaceSubscriptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCurrenciesResponse) ProtoMessage() {}
+func (*ReplaceSubscriptions) ProtoMessage() {}
 
-func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
+func (x *ReplaceSubscriptions) ProtoReflect() protoreflect.Message {
 	mi := &file_currency_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -435,87 +855,2182 @@ func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCurrenciesResponse.ProtoReflect.Descriptor instead.
-func (*ListCurrenciesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReplaceSubscriptions.ProtoReflect.Descriptor instead.
+func (*ReplaceSubscriptions) Descriptor() ([]byte, []int) {
 	return file_currency_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ListCurrenciesResponse) GetCurrencies() []string {
+func (x *ReplaceSubscriptions) GetPairs() []*RateRequest {
 	if x != nil {
-		return x.Currencies
+		return x.Pairs
 	}
 	return nil
 }
 
-var File_currency_proto protoreflect.FileDescriptor
-
-var file_currency_proto_rawDesc = []byte{
+// Heartbeat is sent by clients to signal that a stream is still in use
+type Heartbeat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_currency_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{5}
+}
+
+// Resume continues a previous stream on a new one. The subscriptions of the previous
+// stream replace those of the new stream, and every update published after
+// LastSequence is pushed again. When the server no longer has all of them it pushes
+// the current rate of every pair instead and sets Resync in the acknowledgement.
+type Resume struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Token is the ResumeToken of the previous stream
+	Token string `protobuf:"bytes,1,opt,name=Token,proto3" json:"Token,omitempty"`
+	// LastSequence is the highest Sequence the client received, 0 pushes the current rates
+	LastSequence uint64 `protobuf:"varint,2,opt,name=LastSequence,proto3" json:"LastSequence,omitempty"`
+}
+
+func (x *Resume) Reset() {
+	*x = Resume{}
+	mi := &file_currency_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resume) ProtoMessage() {}
+
+func (x *Resume) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resume.ProtoReflect.Descriptor instead.
+func (*Resume) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Resume) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *Resume) GetLastSequence() uint64 {
+	if x != nil {
+		return x.LastSequence
+	}
+	return 0
+}
+
+// SubscriptionAck acknowledges a successful SubscriptionRequest, failed requests
+// are answered with an error instead
+type SubscriptionAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// RequestId is the RequestId of the acknowledged request
+	RequestId uint64 `protobuf:"varint,1,opt,name=RequestId,proto3" json:"RequestId,omitempty"`
+	// Operation is the operation that was applied
+	Operation SubscriptionOperation `protobuf:"varint,2,opt,name=Operation,proto3,enum=currency.SubscriptionOperation" json:"Operation,omitempty"`
+	// Subscriptions are the pairs the stream is subscribed to after the operation
+	Subscriptions []*RateRequest `protobuf:"bytes,3,rep,name=Subscriptions,proto3" json:"Subscriptions,omitempty"`
+	// ResumeToken identifies the subscriptions of the stream, it can be used to
+	// resume them on a new stream for a limited time after this one closes
+	ResumeToken string `protobuf:"bytes,4,opt,name=ResumeToken,proto3" json:"ResumeToken,omitempty"`
+	// Resync is set on a resume when the missed updates were no longer available,
+	// the current rates of all pairs are pushed instead
+	Resync bool `protobuf:"varint,5,opt,name=Resync,proto3" json:"Resync,omitempty"`
+}
+
+func (x *SubscriptionAck) Reset() {
+	*x = SubscriptionAck{}
+	mi := &file_currency_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionAck) ProtoMessage() {}
+
+func (x *SubscriptionAck) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionAck.ProtoReflect.Descriptor instead.
+func (*SubscriptionAck) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SubscriptionAck) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *SubscriptionAck) GetOperation() SubscriptionOperation {
+	if x != nil {
+		return x.Operation
+	}
+	return SubscriptionOperation_SUBSCRIBE
+}
+
+func (x *SubscriptionAck) GetSubscriptions() []*RateRequest {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *SubscriptionAck) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+func (x *SubscriptionAck) GetResync() bool {
+	if x != nil {
+		return x.Resync
+	}
+	return false
+}
+
+type StreamingRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*StreamingRateResponse_RateResponse
+	//	*StreamingRateResponse_Error
+	//	*StreamingRateResponse_Ack
+	Message isStreamingRateResponse_Message `protobuf_oneof:"message"`
+}
+
+func (x *StreamingRateResponse) Reset() {
+	*x = StreamingRateResponse{}
+	mi := &file_currency_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamingRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamingRateResponse) ProtoMessage() {}
+
+func (x *StreamingRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamingRateResponse.ProtoReflect.Descriptor instead.
+func (*StreamingRateResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{8}
+}
+
+func (m *StreamingRateResponse) GetMessage() isStreamingRateResponse_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *StreamingRateResponse) GetRateResponse() *RateResponse {
+	if x, ok := x.GetMessage().(*StreamingRateResponse_RateResponse); ok {
+		return x.RateResponse
+	}
+	return nil
+}
+
+func (x *StreamingRateResponse) GetError() *status.Status {
+	if x, ok := x.GetMessage().(*StreamingRateResponse_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *StreamingRateResponse) GetAck() *SubscriptionAck {
+	if x, ok := x.GetMessage().(*StreamingRateResponse_Ack); ok {
+		return x.Ack
+	}
+	return nil
+}
+
+type isStreamingRateResponse_Message interface {
+	isStreamingRateResponse_Message()
+}
+
+type StreamingRateResponse_RateResponse struct {
+	RateResponse *RateResponse `protobuf:"bytes,1,opt,name=rate_response,json=rateResponse,proto3,oneof"`
+}
+
+type StreamingRateResponse_Error struct {
+	Error *status.Status `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+type StreamingRateResponse_Ack struct {
+	Ack *SubscriptionAck `protobuf:"bytes,3,opt,name=ack,proto3,oneof"`
+}
+
+func (*StreamingRateResponse_RateResponse) isStreamingRateResponse_Message() {}
+
+func (*StreamingRateResponse_Error) isStreamingRateResponse_Message() {}
+
+func (*StreamingRateResponse_Ack) isStreamingRateResponse_Message() {}
+
+// HistoricalRateRequest defines the request for a GetHistoricalRate call
+type HistoricalRateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Base is the base currency code for the rate
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the destination currency code for the rate
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// Date is the day to return the rate for, formatted as YYYY-MM-DD
+	Date string `protobuf:"bytes,3,opt,name=Date,proto3" json:"Date,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency, takes precedence over Base
+	BaseCode string `protobuf:"bytes,4,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency, takes precedence over Destination
+	DestinationCode string `protobuf:"bytes,5,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *HistoricalRateRequest) Reset() {
+	*x = HistoricalRateRequest{}
+	mi := &file_currency_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoricalRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoricalRateRequest) ProtoMessage() {}
+
+func (x *HistoricalRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoricalRateRequest.ProtoReflect.Descriptor instead.
+func (*HistoricalRateRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HistoricalRateRequest) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *HistoricalRateRequest) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *HistoricalRateRequest) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *HistoricalRateRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *HistoricalRateRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// HistoricalRateResponse is the response from a GetHistoricalRate call. Rates are
+// only published on working days, Date is the publication day the rate was taken from
+// which is the latest one on or before the requested date.
+type HistoricalRateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Base is the base currency code for the rate
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the destination currency code for the rate
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// Rate is the currency rate in effect on Date
+	Rate float64 `protobuf:"fixed64,3,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// Date is the publication day of the rate, formatted as YYYY-MM-DD
+	Date string `protobuf:"bytes,4,opt,name=Date,proto3" json:"Date,omitempty"`
+	// ExactRate is the currency rate as a decimal string
+	ExactRate string `protobuf:"bytes,5,opt,name=ExactRate,proto3" json:"ExactRate,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,6,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,7,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *HistoricalRateResponse) Reset() {
+	*x = HistoricalRateResponse{}
+	mi := &file_currency_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoricalRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoricalRateResponse) ProtoMessage() {}
+
+func (x *HistoricalRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoricalRateResponse.ProtoReflect.Descriptor instead.
+func (*HistoricalRateResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *HistoricalRateResponse) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *HistoricalRateResponse) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *HistoricalRateResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *HistoricalRateResponse) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *HistoricalRateResponse) GetExactRate() string {
+	if x != nil {
+		return x.ExactRate
+	}
+	return ""
+}
+
+func (x *HistoricalRateResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *HistoricalRateResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// RateSeriesRequest defines the request for a GetRateSeries call
+type RateSeriesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Base is the base currency code for the rates
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the destination currency code for the rates
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// From is the first day of the series, formatted as YYYY-MM-DD
+	From string `protobuf:"bytes,3,opt,name=From,proto3" json:"From,omitempty"`
+	// To is the last day of the series inclusive, formatted as YYYY-MM-DD
+	To string `protobuf:"bytes,4,opt,name=To,proto3" json:"To,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency, takes precedence over Base
+	BaseCode string `protobuf:"bytes,5,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency, takes precedence over Destination
+	DestinationCode string `protobuf:"bytes,6,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *RateSeriesRequest) Reset() {
+	*x = RateSeriesRequest{}
+	mi := &file_currency_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateSeriesRequest) ProtoMessage() {}
+
+func (x *RateSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateSeriesRequest.ProtoReflect.Descriptor instead.
+func (*RateSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RateSeriesRequest) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *RateSeriesRequest) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *RateSeriesRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RateSeriesRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *RateSeriesRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RateSeriesRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// RateSeriesResponse is the response from a GetRateSeries call, it contains one
+// entry per publication day in the requested range ordered by date
+type RateSeriesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Base is the base currency code for the rates
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the destination currency code for the rates
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// Rates are the daily rates in the range
+	Rates []*DatedRate `protobuf:"bytes,3,rep,name=Rates,proto3" json:"Rates,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,4,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,5,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *RateSeriesResponse) Reset() {
+	*x = RateSeriesResponse{}
+	mi := &file_currency_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateSeriesResponse) ProtoMessage() {}
+
+func (x *RateSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateSeriesResponse.ProtoReflect.Descriptor instead.
+func (*RateSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RateSeriesResponse) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *RateSeriesResponse) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *RateSeriesResponse) GetRates() []*DatedRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *RateSeriesResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RateSeriesResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// DatedRate is a currency rate published on a given day
+type DatedRate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Date is the publication day, formatted as YYYY-MM-DD
+	Date string `protobuf:"bytes,1,opt,name=Date,proto3" json:"Date,omitempty"`
+	// Rate is the currency rate published on Date
+	Rate float64 `protobuf:"fixed64,2,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// ExactRate is the currency rate as a decimal string
+	ExactRate string `protobuf:"bytes,3,opt,name=ExactRate,proto3" json:"ExactRate,omitempty"`
+}
+
+func (x *DatedRate) Reset() {
+	*x = DatedRate{}
+	mi := &file_currency_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DatedRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DatedRate) ProtoMessage() {}
+
+func (x *DatedRate) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DatedRate.ProtoReflect.Descriptor instead.
+func (*DatedRate) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DatedRate) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DatedRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *DatedRate) GetExactRate() string {
+	if x != nil {
+		return x.ExactRate
+	}
+	return ""
+}
+
+// ConvertRequest defines the request for a Convert call
+type ConvertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Base is the currency code of Amount
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the currency code to convert Amount into
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// Amount is the value to convert, expressed in the Base currency
+	Amount float64 `protobuf:"fixed64,3,opt,name=Amount,proto3" json:"Amount,omitempty"`
+	// RoundingMode selects how the converted amount is rounded, defaults to HALF_EVEN
+	RoundingMode RoundingMode `protobuf:"varint,4,opt,name=RoundingMode,proto3,enum=currency.RoundingMode" json:"RoundingMode,omitempty"`
+	// ExactAmount is the value to convert as a decimal string, e.g. "19.99".
+	// When set it takes precedence over Amount.
+	ExactAmount string `protobuf:"bytes,5,opt,name=ExactAmount,proto3" json:"ExactAmount,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency, takes precedence over Base
+	BaseCode string `protobuf:"bytes,6,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency, takes precedence over Destination
+	DestinationCode string `protobuf:"bytes,7,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_currency_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConvertRequest) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *ConvertRequest) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *ConvertRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertRequest) GetRoundingMode() RoundingMode {
+	if x != nil {
+		return x.RoundingMode
+	}
+	return RoundingMode_HALF_EVEN
+}
+
+func (x *ConvertRequest) GetExactAmount() string {
+	if x != nil {
+		return x.ExactAmount
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// ConvertResponse is the response from a Convert call
+type ConvertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Base is the currency code of the original amount
+	Base Currencies `protobuf:"varint,1,opt,name=Base,proto3,enum=currency.Currencies" json:"Base,omitempty"`
+	// Destination is the currency code of the converted amount
+	Destination Currencies `protobuf:"varint,2,opt,name=Destination,proto3,enum=currency.Currencies" json:"Destination,omitempty"`
+	// Amount is the converted value rounded to MinorUnits decimal places
+	Amount float64 `protobuf:"fixed64,3,opt,name=Amount,proto3" json:"Amount,omitempty"`
+	// Rate is the currency rate used for the conversion
+	Rate float64 `protobuf:"fixed64,4,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// MinorUnits is the number of decimal places of the Destination currency
+	MinorUnits int32 `protobuf:"varint,5,opt,name=MinorUnits,proto3" json:"MinorUnits,omitempty"`
+	// ExactAmount is the converted value as a decimal string with MinorUnits decimal places
+	ExactAmount string `protobuf:"bytes,6,opt,name=ExactAmount,proto3" json:"ExactAmount,omitempty"`
+	// ExactRate is the currency rate used for the conversion as a decimal string
+	ExactRate string `protobuf:"bytes,7,opt,name=ExactRate,proto3" json:"ExactRate,omitempty"`
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,8,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,9,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_currency_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConvertResponse) GetBase() Currencies {
+	if x != nil {
+		return x.Base
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *ConvertResponse) GetDestination() Currencies {
+	if x != nil {
+		return x.Destination
+	}
+	return Currencies_UNKNOWN
+}
+
+func (x *ConvertResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetMinorUnits() int32 {
+	if x != nil {
+		return x.MinorUnits
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetExactAmount() string {
+	if x != nil {
+		return x.ExactAmount
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetExactRate() string {
+	if x != nil {
+		return x.ExactRate
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+// RatesRequest defines the request for a GetRates call. Pairs can be given as a base
+// currency with a list of destinations, as explicit pairs, or both.
+type RatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BaseCode is the ISO 4217 code of the base currency for DestinationCodes
+	BaseCode string `protobuf:"bytes,1,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCodes are the ISO 4217 codes to return rates from BaseCode for
+	DestinationCodes []string `protobuf:"bytes,2,rep,name=DestinationCodes,proto3" json:"DestinationCodes,omitempty"`
+	// Pairs are additional currency pairs to return rates for
+	Pairs []*RateRequest `protobuf:"bytes,3,rep,name=Pairs,proto3" json:"Pairs,omitempty"`
+}
+
+func (x *RatesRequest) Reset() {
+	*x = RatesRequest{}
+	mi := &file_currency_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatesRequest) ProtoMessage() {}
+
+func (x *RatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatesRequest.ProtoReflect.Descriptor instead.
+func (*RatesRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RatesRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *RatesRequest) GetDestinationCodes() []string {
+	if x != nil {
+		return x.DestinationCodes
+	}
+	return nil
+}
+
+func (x *RatesRequest) GetPairs() []*RateRequest {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+// RatesResponse is the response from a GetRates call, it contains one result per
+// requested pair in request order, DestinationCodes first followed by Pairs
+type RatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*RateResult `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+}
+
+func (x *RatesResponse) Reset() {
+	*x = RatesResponse{}
+	mi := &file_currency_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatesResponse) ProtoMessage() {}
+
+func (x *RatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatesResponse.ProtoReflect.Descriptor instead.
+func (*RatesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RatesResponse) GetResults() []*RateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// RateResult is the rate for a single pair of a GetRates call, a pair which could
+// not be priced carries an error with the RateRequest attached as a detail
+type RateResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Result:
+	//	*RateResult_RateResponse
+	//	*RateResult_Error
+	Result isRateResult_Result `protobuf_oneof:"result"`
+}
+
+func (x *RateResult) Reset() {
+	*x = RateResult{}
+	mi := &file_currency_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateResult) ProtoMessage() {}
+
+func (x *RateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateResult.ProtoReflect.Descriptor instead.
+func (*RateResult) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{18}
+}
+
+func (m *RateResult) GetResult() isRateResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *RateResult) GetRateResponse() *RateResponse {
+	if x, ok := x.GetResult().(*RateResult_RateResponse); ok {
+		return x.RateResponse
+	}
+	return nil
+}
+
+func (x *RateResult) GetError() *status.Status {
+	if x, ok := x.GetResult().(*RateResult_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isRateResult_Result interface {
+	isRateResult_Result()
+}
+
+type RateResult_RateResponse struct {
+	RateResponse *RateResponse `protobuf:"bytes,1,opt,name=rate_response,json=rateResponse,proto3,oneof"`
+}
+
+type RateResult_Error struct {
+	Error *status.Status `protobuf:"bytes,2,opt,name=error,proto3,oneof"`
+}
+
+func (*RateResult_RateResponse) isRateResult_Result() {}
+
+func (*RateResult_Error) isRateResult_Result() {}
+
+// CandlesRequest defines the request for a GetCandles call
+type CandlesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,1,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,2,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Interval is the length of every bucket
+	Interval CandleInterval `protobuf:"varint,3,opt,name=Interval,proto3,enum=currency.CandleInterval" json:"Interval,omitempty"`
+	// From is the start of the first bucket, the oldest recorded rate when not set
+	From *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=From,proto3" json:"From,omitempty"`
+	// To is the end of the last bucket, the current time when not set
+	To *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=To,proto3" json:"To,omitempty"`
+}
+
+func (x *CandlesRequest) Reset() {
+	*x = CandlesRequest{}
+	mi := &file_currency_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesRequest) ProtoMessage() {}
+
+func (x *CandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesRequest.ProtoReflect.Descriptor instead.
+func (*CandlesRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CandlesRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *CandlesRequest) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_ONE_MINUTE
+}
+
+func (x *CandlesRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *CandlesRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// CandlesResponse is the response from a GetCandles call, buckets in which no rate
+// was published are left out
+type CandlesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,1,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,2,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Interval is the length of every bucket
+	Interval CandleInterval `protobuf:"varint,3,opt,name=Interval,proto3,enum=currency.CandleInterval" json:"Interval,omitempty"`
+	// Candles are ordered by their start
+	Candles []*Candle `protobuf:"bytes,4,rep,name=Candles,proto3" json:"Candles,omitempty"`
+}
+
+func (x *CandlesResponse) Reset() {
+	*x = CandlesResponse{}
+	mi := &file_currency_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandlesResponse) ProtoMessage() {}
+
+func (x *CandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandlesResponse.ProtoReflect.Descriptor instead.
+func (*CandlesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CandlesResponse) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *CandlesResponse) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_ONE_MINUTE
+}
+
+func (x *CandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+// Candle is the movement of a rate during a single bucket
+type Candle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Start is the beginning of the bucket
+	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=Start,proto3" json:"Start,omitempty"`
+	// Open is the first rate published in the bucket
+	Open float64 `protobuf:"fixed64,2,opt,name=Open,proto3" json:"Open,omitempty"`
+	// High is the highest rate published in the bucket
+	High float64 `protobuf:"fixed64,3,opt,name=High,proto3" json:"High,omitempty"`
+	// Low is the lowest rate published in the bucket
+	Low float64 `protobuf:"fixed64,4,opt,name=Low,proto3" json:"Low,omitempty"`
+	// Close is the last rate published in the bucket
+	Close float64 `protobuf:"fixed64,5,opt,name=Close,proto3" json:"Close,omitempty"`
+	// Samples is the number of rates published in the bucket
+	Samples uint32 `protobuf:"varint,6,opt,name=Samples,proto3" json:"Samples,omitempty"`
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_currency_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Candle) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetSamples() uint32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+// SetOverrideRequest pins the rate of a pair, the inverse pair gets the inverse rate
+type SetOverrideRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,1,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,2,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Rate is the pinned rate as a decimal string, e.g. "1.0892"
+	Rate string `protobuf:"bytes,3,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// ExpiresAt is when the override ends, unset keeps it until it is cleared
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	// Reason is a note for other operators, e.g. a ticket number
+	Reason string `protobuf:"bytes,5,opt,name=Reason,proto3" json:"Reason,omitempty"`
+}
+
+func (x *SetOverrideRequest) Reset() {
+	*x = SetOverrideRequest{}
+	mi := &file_currency_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverrideRequest) ProtoMessage() {}
+
+func (x *SetOverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverrideRequest.ProtoReflect.Descriptor instead.
+func (*SetOverrideRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetOverrideRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *SetOverrideRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *SetOverrideRequest) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *SetOverrideRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *SetOverrideRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Override is a pinned rate
+type Override struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,1,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,2,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+	// Rate is the pinned rate as a decimal string
+	Rate string `protobuf:"bytes,3,opt,name=Rate,proto3" json:"Rate,omitempty"`
+	// ExpiresAt is when the override ends, unset when it never expires
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ExpiresAt,proto3" json:"ExpiresAt,omitempty"`
+	// Reason is the note given when the override was set
+	Reason string `protobuf:"bytes,5,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	// CreatedBy is the ID of the client which set the override
+	CreatedBy string `protobuf:"bytes,6,opt,name=CreatedBy,proto3" json:"CreatedBy,omitempty"`
+	// CreatedAt is the time the override was set
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+}
+
+func (x *Override) Reset() {
+	*x = Override{}
+	mi := &file_currency_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Override) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Override) ProtoMessage() {}
+
+func (x *Override) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Override.ProtoReflect.Descriptor instead.
+func (*Override) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Override) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *Override) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+func (x *Override) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *Override) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Override) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Override) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Override) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListOverridesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Overrides []*Override `protobuf:"bytes,1,rep,name=Overrides,proto3" json:"Overrides,omitempty"`
+}
+
+func (x *ListOverridesResponse) Reset() {
+	*x = ListOverridesResponse{}
+	mi := &file_currency_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOverridesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOverridesResponse) ProtoMessage() {}
+
+func (x *ListOverridesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOverridesResponse.ProtoReflect.Descriptor instead.
+func (*ListOverridesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListOverridesResponse) GetOverrides() []*Override {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+// ClearOverrideRequest removes the override of a pair
+type ClearOverrideRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// BaseCode is the ISO 4217 code of the base currency
+	BaseCode string `protobuf:"bytes,1,opt,name=BaseCode,proto3" json:"BaseCode,omitempty"`
+	// DestinationCode is the ISO 4217 code of the destination currency
+	DestinationCode string `protobuf:"bytes,2,opt,name=DestinationCode,proto3" json:"DestinationCode,omitempty"`
+}
+
+func (x *ClearOverrideRequest) Reset() {
+	*x = ClearOverrideRequest{}
+	mi := &file_currency_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearOverrideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearOverrideRequest) ProtoMessage() {}
+
+func (x *ClearOverrideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearOverrideRequest.ProtoReflect.Descriptor instead.
+func (*ClearOverrideRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ClearOverrideRequest) GetBaseCode() string {
+	if x != nil {
+		return x.BaseCode
+	}
+	return ""
+}
+
+func (x *ClearOverrideRequest) GetDestinationCode() string {
+	if x != nil {
+		return x.DestinationCode
+	}
+	return ""
+}
+
+type Empty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *Empty) Reset() {
+	*x = Empty{}
+	mi := &file_currency_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Empty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Empty) ProtoMessage() {}
+
+func (x *Empty) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Empty.ProtoReflect.Descriptor instead.
+func (*Empty) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{26}
+}
+
+type ListCurrenciesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Currencies []string `protobuf:"bytes,1,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	// overridden are the currencies of the pairs with an active override
+	Overridden []string `protobuf:"bytes,2,rep,name=overridden,proto3" json:"overridden,omitempty"`
+}
+
+func (x *ListCurrenciesResponse) Reset() {
+	*x = ListCurrenciesResponse{}
+	mi := &file_currency_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCurrenciesResponse) ProtoMessage() {}
+
+func (x *ListCurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*ListCurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListCurrenciesResponse) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *ListCurrenciesResponse) GetOverridden() []string {
+	if x != nil {
+		return x.Overridden
+	}
+	return nil
+}
+
+var File_currency_proto protoreflect.FileDescriptor
+
+var file_currency_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x1a, 0x17, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x22, 0x6f, 0x0a, 0x0b, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72,
-	0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b,
-	0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72,
-	0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x22, 0x84, 0x01, 0x0a, 0x0c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20,
+	0x12, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb5, 0x01, 0x0a, 0x0b, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x36,
+	0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43,
-	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12,
-	0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
-	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74,
-	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x22, 0x8d, 0x01, 0x0a, 0x15,
-	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63,
-	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x07, 0x0a, 0x05, 0x45,
-	0x6d, 0x70, 0x74, 0x79, 0x22, 0x38, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72,
-	0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e,
-	0x0a, 0x0a, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0a, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x2a, 0xc2,
-	0x02, 0x0a, 0x0a, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x0b, 0x0a,
-	0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x55,
-	0x52, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x53, 0x44, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03,
-	0x4a, 0x50, 0x59, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x47, 0x4e, 0x10, 0x04, 0x12, 0x07,
-	0x0a, 0x03, 0x43, 0x5a, 0x4b, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x44, 0x4b, 0x4b, 0x10, 0x06,
-	0x12, 0x07, 0x0a, 0x03, 0x47, 0x42, 0x50, 0x10, 0x07, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x55, 0x46,
-	0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4c, 0x4e, 0x10, 0x09, 0x12, 0x07, 0x0a, 0x03, 0x52,
-	0x4f, 0x4e, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x45, 0x4b, 0x10, 0x0b, 0x12, 0x07, 0x0a,
-	0x03, 0x43, 0x48, 0x46, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x53, 0x4b, 0x10, 0x0d, 0x12,
-	0x07, 0x0a, 0x03, 0x4e, 0x4f, 0x4b, 0x10, 0x0e, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x52, 0x4b, 0x10,
-	0x0f, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x42, 0x10, 0x10, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52,
-	0x59, 0x10, 0x11, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x55, 0x44, 0x10, 0x12, 0x12, 0x07, 0x0a, 0x03,
-	0x42, 0x52, 0x4c, 0x10, 0x13, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x41, 0x44, 0x10, 0x14, 0x12, 0x07,
-	0x0a, 0x03, 0x43, 0x4e, 0x59, 0x10, 0x15, 0x12, 0x07, 0x0a, 0x03, 0x48, 0x4b, 0x44, 0x10, 0x16,
-	0x12, 0x07, 0x0a, 0x03, 0x49, 0x44, 0x52, 0x10, 0x17, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4c, 0x53,
-	0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x52, 0x10, 0x19, 0x12, 0x07, 0x0a, 0x03, 0x4b,
-	0x52, 0x57, 0x10, 0x1a, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x58, 0x4e, 0x10, 0x1b, 0x12, 0x07, 0x0a,
-	0x03, 0x4d, 0x59, 0x52, 0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x5a, 0x44, 0x10, 0x1d, 0x12,
-	0x07, 0x0a, 0x03, 0x50, 0x48, 0x50, 0x10, 0x1e, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x47, 0x44, 0x10,
-	0x1f, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x48, 0x42, 0x10, 0x20, 0x12, 0x07, 0x0a, 0x03, 0x5a, 0x41,
-	0x52, 0x10, 0x21, 0x32, 0xd7, 0x01, 0x0a, 0x08, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
-	0x12, 0x38, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x15, 0x2e, 0x63, 0x75,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xd2, 0x03, 0x0a,
+	0x0c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52,
+	0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x78, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x78, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a,
+	0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x41, 0x73, 0x4f, 0x66, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x41,
+	0x73, 0x4f, 0x66, 0x12, 0x38, 0x0a, 0x09, 0x45, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x45, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x50, 0x61, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x18, 0x0a, 0x07, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x53,
+	0x74, 0x61, 0x6c, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x53, 0x74, 0x61, 0x6c,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x64, 0x65,
+	0x6e, 0x22, 0xb0, 0x04, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42,
+	0x61, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b,
+	0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x42,
+	0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42,
+	0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12,
+	0x37, 0x0a, 0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1d, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x75,
 	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0e, 0x53, 0x75,
-	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x61, 0x74, 0x65, 0x73, 0x12, 0x15, 0x2e, 0x63,
-	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x53,
-	0x74, 0x72, 0x65, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74,
-	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x63, 0x75, 0x72,
-	0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x20, 0x2e, 0x63, 0x75,
-	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65,
-	0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3e, 0x5a,
+	0x73, 0x74, 0x48, 0x00, 0x52, 0x09, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12,
+	0x39, 0x0a, 0x0b, 0x75, 0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x75,
+	0x6e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x12, 0x3a, 0x0a, 0x07, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x07, 0x72,
+	0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x12, 0x33, 0x0a, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62,
+	0x65, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x48, 0x00,
+	0x52, 0x09, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x48, 0x00, 0x52,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x22, 0xb1, 0x01, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0c,
+	0x4d, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x4d, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x70, 0x73,
+	0x12, 0x3b, 0x0a, 0x0b, 0x4d, 0x69, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x0b, 0x4d, 0x69, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x39, 0x0a,
+	0x0a, 0x4d, 0x61, 0x78, 0x53, 0x69, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x4d, 0x61,
+	0x78, 0x53, 0x69, 0x6c, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x43, 0x0a, 0x14, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x63, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x2b, 0x0a, 0x05, 0x50, 0x61, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x50, 0x61, 0x69, 0x72, 0x73, 0x22, 0x0b, 0x0a,
+	0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x22, 0x42, 0x0a, 0x06, 0x52, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x4c, 0x61,
+	0x73, 0x74, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0c, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x22, 0xe5,
+	0x01, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x41,
+	0x63, 0x6b, 0x12, 0x1c, 0x0a, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64,
+	0x12, 0x3d, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x3b, 0x0a, 0x0d, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x0d, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b,
+	0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x52, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x52, 0x65, 0x73, 0x79, 0x6e, 0x63, 0x22, 0xbc, 0x01, 0x0a, 0x15, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3d, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
+	0x00, 0x52, 0x0c, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x2d, 0x0a, 0x03, 0x61,
+	0x63, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xd3, 0x01, 0x0a, 0x15, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14,
+	0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x86, 0x02, 0x0a, 0x16,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
+	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65,
+	0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x44, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x61, 0x74, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x45, 0x78, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x78, 0x61, 0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65,
+	0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x64, 0x65, 0x22, 0xdf, 0x01, 0x0a, 0x11, 0x52, 0x61, 0x74, 0x65, 0x53, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61,
+	0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04,
+	0x42, 0x61, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52,
+	0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04,
+	0x46, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d,
+	0x12, 0x0e, 0x0a, 0x02, 0x54, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x54, 0x6f,
+	0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f,
+	0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x22, 0xe7, 0x01, 0x0a, 0x12, 0x52, 0x61, 0x74, 0x65, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a,
+	0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69,
+	0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69,
+	0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x29, 0x0a, 0x05, 0x52, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x44, 0x61, 0x74, 0x65, 0x64, 0x52,
+	0x61, 0x74, 0x65, 0x52, 0x05, 0x52, 0x61, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65,
+	0x22, 0x51, 0x0a, 0x09, 0x44, 0x61, 0x74, 0x65, 0x64, 0x52, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x44, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x44, 0x61, 0x74,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x04, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x78, 0x61, 0x63, 0x74, 0x52, 0x61,
+	0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x78, 0x61, 0x63, 0x74, 0x52,
+	0x61, 0x74, 0x65, 0x22, 0xae, 0x02, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
+	0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61, 0x73, 0x65,
+	0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x3a, 0x0a, 0x0c, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2e, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x0c,
+	0x52, 0x6f, 0x75, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x45, 0x78, 0x61, 0x63, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x45, 0x78, 0x61, 0x63, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a,
+	0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65,
+	0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x64, 0x65, 0x22, 0xc5, 0x02, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x04, 0x42, 0x61, 0x73, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x04, 0x42, 0x61,
+	0x73, 0x65, 0x12, 0x36, 0x0a, 0x0b, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x2e, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x0b, 0x44,
+	0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x41, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x4d, 0x69, 0x6e, 0x6f, 0x72, 0x55,
+	0x6e, 0x69, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x4d, 0x69, 0x6e, 0x6f,
+	0x72, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x45, 0x78, 0x61, 0x63, 0x74, 0x41,
+	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x45, 0x78, 0x61,
+	0x63, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x45, 0x78, 0x61, 0x63,
+	0x74, 0x52, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x45, 0x78, 0x61,
+	0x63, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x22, 0x83, 0x01, 0x0a,
+	0x0c, 0x52, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2a, 0x0a, 0x10, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x10, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x50, 0x61, 0x69, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x50, 0x61, 0x69,
+	0x72, 0x73, 0x22, 0x3f, 0x0a, 0x0d, 0x52, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
+	0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0a, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x3d, 0x0a, 0x0d, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x48, 0x00, 0x52, 0x0c, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2a, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x08, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0xe8, 0x01, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x64,
+	0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61,
+	0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x34, 0x0a, 0x08, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x52, 0x08, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x2e, 0x0a, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x04, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x54, 0x6f, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02,
+	0x54, 0x6f, 0x22, 0xb9, 0x01, 0x0a, 0x0f, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73,
+	0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x34, 0x0a, 0x08,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18,
+	0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x52, 0x08, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x12, 0x2a, 0x0a, 0x07, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43,
+	0x61, 0x6e, 0x64, 0x6c, 0x65, 0x52, 0x07, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x22, 0xa4,
+	0x01, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x53, 0x74, 0x61, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x4f,
+	0x70, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x48, 0x69, 0x67, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x48,
+	0x69, 0x67, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x4c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x4c, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x53,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x53, 0x61,
+	0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x73, 0x41, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x8e, 0x02, 0x0a, 0x08, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x52,
+	0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x52, 0x61, 0x74, 0x65, 0x12,
+	0x38, 0x0a, 0x09, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x45, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x79, 0x12,
+	0x38, 0x0a, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x49, 0x0a, 0x15, 0x4c, 0x69, 0x73,
+	0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x30, 0x0a, 0x09, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x2e, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x09, 0x4f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x65, 0x73, 0x22, 0x5c, 0x0a, 0x14, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x42, 0x61, 0x73, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x74,
+	0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x44, 0x65, 0x73, 0x74, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
+	0x64, 0x65, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x58, 0x0a, 0x16, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64,
+	0x64, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x76, 0x65, 0x72, 0x72,
+	0x69, 0x64, 0x64, 0x65, 0x6e, 0x2a, 0x5f, 0x0a, 0x15, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0d,
+	0x0a, 0x09, 0x53, 0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x10, 0x00, 0x12, 0x0f, 0x0a,
+	0x0b, 0x55, 0x4e, 0x53, 0x55, 0x42, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x10, 0x01, 0x12, 0x0b,
+	0x0a, 0x07, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x43, 0x45, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x48,
+	0x45, 0x41, 0x52, 0x54, 0x42, 0x45, 0x41, 0x54, 0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45,
+	0x53, 0x55, 0x4d, 0x45, 0x10, 0x04, 0x2a, 0x40, 0x0a, 0x0e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x4e, 0x45, 0x5f,
+	0x4d, 0x49, 0x4e, 0x55, 0x54, 0x45, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x49, 0x56, 0x45,
+	0x5f, 0x4d, 0x49, 0x4e, 0x55, 0x54, 0x45, 0x53, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x4f, 0x4e,
+	0x45, 0x5f, 0x48, 0x4f, 0x55, 0x52, 0x10, 0x02, 0x2a, 0x38, 0x0a, 0x0c, 0x52, 0x6f, 0x75, 0x6e,
+	0x64, 0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x48, 0x41, 0x4c, 0x46,
+	0x5f, 0x45, 0x56, 0x45, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x48, 0x41, 0x4c, 0x46, 0x5f,
+	0x55, 0x50, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x52, 0x55, 0x4e, 0x43, 0x41, 0x54, 0x45,
+	0x10, 0x02, 0x2a, 0xc2, 0x02, 0x0a, 0x0a, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x07,
+	0x0a, 0x03, 0x45, 0x55, 0x52, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x53, 0x44, 0x10, 0x02,
+	0x12, 0x07, 0x0a, 0x03, 0x4a, 0x50, 0x59, 0x10, 0x03, 0x12, 0x07, 0x0a, 0x03, 0x42, 0x47, 0x4e,
+	0x10, 0x04, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x5a, 0x4b, 0x10, 0x05, 0x12, 0x07, 0x0a, 0x03, 0x44,
+	0x4b, 0x4b, 0x10, 0x06, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x42, 0x50, 0x10, 0x07, 0x12, 0x07, 0x0a,
+	0x03, 0x48, 0x55, 0x46, 0x10, 0x08, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4c, 0x4e, 0x10, 0x09, 0x12,
+	0x07, 0x0a, 0x03, 0x52, 0x4f, 0x4e, 0x10, 0x0a, 0x12, 0x07, 0x0a, 0x03, 0x53, 0x45, 0x4b, 0x10,
+	0x0b, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x48, 0x46, 0x10, 0x0c, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x53,
+	0x4b, 0x10, 0x0d, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x4f, 0x4b, 0x10, 0x0e, 0x12, 0x07, 0x0a, 0x03,
+	0x48, 0x52, 0x4b, 0x10, 0x0f, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x55, 0x42, 0x10, 0x10, 0x12, 0x07,
+	0x0a, 0x03, 0x54, 0x52, 0x59, 0x10, 0x11, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x55, 0x44, 0x10, 0x12,
+	0x12, 0x07, 0x0a, 0x03, 0x42, 0x52, 0x4c, 0x10, 0x13, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x41, 0x44,
+	0x10, 0x14, 0x12, 0x07, 0x0a, 0x03, 0x43, 0x4e, 0x59, 0x10, 0x15, 0x12, 0x07, 0x0a, 0x03, 0x48,
+	0x4b, 0x44, 0x10, 0x16, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x44, 0x52, 0x10, 0x17, 0x12, 0x07, 0x0a,
+	0x03, 0x49, 0x4c, 0x53, 0x10, 0x18, 0x12, 0x07, 0x0a, 0x03, 0x49, 0x4e, 0x52, 0x10, 0x19, 0x12,
+	0x07, 0x0a, 0x03, 0x4b, 0x52, 0x57, 0x10, 0x1a, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x58, 0x4e, 0x10,
+	0x1b, 0x12, 0x07, 0x0a, 0x03, 0x4d, 0x59, 0x52, 0x10, 0x1c, 0x12, 0x07, 0x0a, 0x03, 0x4e, 0x5a,
+	0x44, 0x10, 0x1d, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x48, 0x50, 0x10, 0x1e, 0x12, 0x07, 0x0a, 0x03,
+	0x53, 0x47, 0x44, 0x10, 0x1f, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x48, 0x42, 0x10, 0x20, 0x12, 0x07,
+	0x0a, 0x03, 0x5a, 0x41, 0x52, 0x10, 0x21, 0x32, 0xc3, 0x04, 0x0a, 0x08, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x12, 0x38, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12,
+	0x15, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54,
+	0x0a, 0x0e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x52, 0x61, 0x74, 0x65, 0x73,
+	0x12, 0x1d, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1f, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x69, 0x6e, 0x67, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x28, 0x01, 0x30, 0x01, 0x12, 0x43, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x69, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x20, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x63, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x56, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1f,
+	0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x20, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x69, 0x63, 0x61, 0x6c, 0x52, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x4a, 0x0a, 0x0d, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x53, 0x65, 0x72, 0x69,
+	0x65, 0x73, 0x12, 0x1b, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61,
+	0x74, 0x65, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1c, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x53,
+	0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a,
+	0x07, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x12, 0x18, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x6f,
+	0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
+	0x08, 0x47, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x52, 0x61, 0x74,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a, 0x0a, 0x47, 0x65,
+	0x74, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x63, 0x79, 0x2e, 0x43, 0x61, 0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x61,
+	0x6e, 0x64, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0xcd, 0x01,
+	0x0a, 0x05, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x3f, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x4f, 0x76,
+	0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x1c, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63,
+	0x79, 0x2e, 0x53, 0x65, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e,
+	0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x41, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74,
+	0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x0f, 0x2e, 0x63, 0x75, 0x72, 0x72,
+	0x65, 0x6e, 0x63, 0x79, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x1f, 0x2e, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
+	0x64, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0d, 0x43,
+	0x6c, 0x65, 0x61, 0x72, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x12, 0x1e, 0x2e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x4f, 0x76, 0x65,
+	0x72, 0x72, 0x69, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x42, 0x3e, 0x5a,
 	0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x68, 0x76,
 	0x65, 0x63, 0x69, 0x6b, 0x61, 0x61, 0x6e, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x69, 0x6e, 0x67,
 	0x4d, 0x69, 0x63, 0x72, 0x6f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x2f, 0x63, 0x75,
@@ -535,35 +3050,123 @@ func file_currency_proto_rawDescGZIP() []byte {
 	return file_currency_proto_rawDescData
 }
 
-var file_currency_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_currency_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_currency_proto_goTypes = []any{
-	(Currencies)(0),                // 0: currency.Currencies
-	(*RateRequest)(nil),            // 1: currency.RateRequest
-	(*RateResponse)(nil),           // 2: currency.RateResponse
-	(*StreamingRateResponse)(nil),  // 3: currency.StreamingRateResponse
-	(*Empty)(nil),                  // 4: currency.Empty
-	(*ListCurrenciesResponse)(nil), // 5: currency.ListCurrenciesResponse
-	(*status.Status)(nil),          // 6: google.rpc.Status
+	(SubscriptionOperation)(0),     // 0: currency.SubscriptionOperation
+	(CandleInterval)(0),            // 1: currency.CandleInterval
+	(RoundingMode)(0),              // 2: currency.RoundingMode
+	(Currencies)(0),                // 3: currency.Currencies
+	(*RateRequest)(nil),            // 4: currency.RateRequest
+	(*RateResponse)(nil),           // 5: currency.RateResponse
+	(*SubscriptionRequest)(nil),    // 6: currency.SubscriptionRequest
+	(*SubscriptionOptions)(nil),    // 7: currency.SubscriptionOptions
+	(*ReplaceSubscriptions)(nil),   // 8: currency.ReplaceSubscriptions
+	(*Heartbeat)(nil),              // 9: currency.Heartbeat
+	(*Resume)(nil),                 // 10: currency.Resume
+	(*SubscriptionAck)(nil),        // 11: currency.SubscriptionAck
+	(*StreamingRateResponse)(nil),  // 12: currency.StreamingRateResponse
+	(*HistoricalRateRequest)(nil),  // 13: currency.HistoricalRateRequest
+	(*HistoricalRateResponse)(nil), // 14: currency.HistoricalRateResponse
+	(*RateSeriesRequest)(nil),      // 15: currency.RateSeriesRequest
+	(*RateSeriesResponse)(nil),     // 16: currency.RateSeriesResponse
+	(*DatedRate)(nil),              // 17: currency.DatedRate
+	(*ConvertRequest)(nil),         // 18: currency.ConvertRequest
+	(*ConvertResponse)(nil),        // 19: currency.ConvertResponse
+	(*RatesRequest)(nil),           // 20: currency.RatesRequest
+	(*RatesResponse)(nil),          // 21: currency.RatesResponse
+	(*RateResult)(nil),             // 22: currency.RateResult
+	(*CandlesRequest)(nil),         // 23: currency.CandlesRequest
+	(*CandlesResponse)(nil),        // 24: currency.CandlesResponse
+	(*Candle)(nil),                 // 25: currency.Candle
+	(*SetOverrideRequest)(nil),     // 26: currency.SetOverrideRequest
+	(*Override)(nil),               // 27: currency.Override
+	(*ListOverridesResponse)(nil),  // 28: currency.ListOverridesResponse
+	(*ClearOverrideRequest)(nil),   // 29: currency.ClearOverrideRequest
+	(*Empty)(nil),                  // 30: currency.Empty
+	(*ListCurrenciesResponse)(nil), // 31: currency.ListCurrenciesResponse
+	(*timestamppb.Timestamp)(nil),  // 32: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 33: google.protobuf.Duration
+	(*status.Status)(nil),          // 34: google.rpc.Status
 }
 var file_currency_proto_depIdxs = []int32{
-	0, // 0: currency.RateRequest.Base:type_name -> currency.Currencies
-	0, // 1: currency.RateRequest.Destination:type_name -> currency.Currencies
-	0, // 2: currency.RateResponse.Base:type_name -> currency.Currencies
-	0, // 3: currency.RateResponse.Destination:type_name -> currency.Currencies
-	2, // 4: currency.StreamingRateResponse.rate_response:type_name -> currency.RateResponse
-	6, // 5: currency.StreamingRateResponse.error:type_name -> google.rpc.Status
-	1, // 6: currency.Currency.GetRate:input_type -> currency.RateRequest
-	1, // 7: currency.Currency.SubscribeRates:input_type -> currency.RateRequest
-	4, // 8: currency.Currency.ListCurrencies:input_type -> currency.Empty
-	2, // 9: currency.Currency.GetRate:output_type -> currency.RateResponse
-	3, // 10: currency.Currency.SubscribeRates:output_type -> currency.StreamingRateResponse
-	5, // 11: currency.Currency.ListCurrencies:output_type -> currency.ListCurrenciesResponse
-	9, // [9:12] is the sub-list for method output_type
-	6, // [6:9] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	3,  // 0: currency.RateRequest.Base:type_name -> currency.Currencies
+	3,  // 1: currency.RateRequest.Destination:type_name -> currency.Currencies
+	3,  // 2: currency.RateResponse.Base:type_name -> currency.Currencies
+	3,  // 3: currency.RateResponse.Destination:type_name -> currency.Currencies
+	32, // 4: currency.RateResponse.AsOf:type_name -> google.protobuf.Timestamp
+	32, // 5: currency.RateResponse.EmittedAt:type_name -> google.protobuf.Timestamp
+	3,  // 6: currency.SubscriptionRequest.Base:type_name -> currency.Currencies
+	3,  // 7: currency.SubscriptionRequest.Destination:type_name -> currency.Currencies
+	7,  // 8: currency.SubscriptionRequest.Options:type_name -> currency.SubscriptionOptions
+	4,  // 9: currency.SubscriptionRequest.subscribe:type_name -> currency.RateRequest
+	4,  // 10: currency.SubscriptionRequest.unsubscribe:type_name -> currency.RateRequest
+	8,  // 11: currency.SubscriptionRequest.replace:type_name -> currency.ReplaceSubscriptions
+	9,  // 12: currency.SubscriptionRequest.heartbeat:type_name -> currency.Heartbeat
+	10, // 13: currency.SubscriptionRequest.resume:type_name -> currency.Resume
+	33, // 14: currency.SubscriptionOptions.MinInterval:type_name -> google.protobuf.Duration
+	33, // 15: currency.SubscriptionOptions.MaxSilence:type_name -> google.protobuf.Duration
+	4,  // 16: currency.ReplaceSubscriptions.Pairs:type_name -> currency.RateRequest
+	0,  // 17: currency.SubscriptionAck.Operation:type_name -> currency.SubscriptionOperation
+	4,  // 18: currency.SubscriptionAck.Subscriptions:type_name -> currency.RateRequest
+	5,  // 19: currency.StreamingRateResponse.rate_response:type_name -> currency.RateResponse
+	34, // 20: currency.StreamingRateResponse.error:type_name -> google.rpc.Status
+	11, // 21: currency.StreamingRateResponse.ack:type_name -> currency.SubscriptionAck
+	3,  // 22: currency.HistoricalRateRequest.Base:type_name -> currency.Currencies
+	3,  // 23: currency.HistoricalRateRequest.Destination:type_name -> currency.Currencies
+	3,  // 24: currency.HistoricalRateResponse.Base:type_name -> currency.Currencies
+	3,  // 25: currency.HistoricalRateResponse.Destination:type_name -> currency.Currencies
+	3,  // 26: currency.RateSeriesRequest.Base:type_name -> currency.Currencies
+	3,  // 27: currency.RateSeriesRequest.Destination:type_name -> currency.Currencies
+	3,  // 28: currency.RateSeriesResponse.Base:type_name -> currency.Currencies
+	3,  // 29: currency.RateSeriesResponse.Destination:type_name -> currency.Currencies
+	17, // 30: currency.RateSeriesResponse.Rates:type_name -> currency.DatedRate
+	3,  // 31: currency.ConvertRequest.Base:type_name -> currency.Currencies
+	3,  // 32: currency.ConvertRequest.Destination:type_name -> currency.Currencies
+	2,  // 33: currency.ConvertRequest.RoundingMode:type_name -> currency.RoundingMode
+	3,  // 34: currency.ConvertResponse.Base:type_name -> currency.Currencies
+	3,  // 35: currency.ConvertResponse.Destination:type_name -> currency.Currencies
+	4,  // 36: currency.RatesRequest.Pairs:type_name -> currency.RateRequest
+	22, // 37: currency.RatesResponse.Results:type_name -> currency.RateResult
+	5,  // 38: currency.RateResult.rate_response:type_name -> currency.RateResponse
+	34, // 39: currency.RateResult.error:type_name -> google.rpc.Status
+	1,  // 40: currency.CandlesRequest.Interval:type_name -> currency.CandleInterval
+	32, // 41: currency.CandlesRequest.From:type_name -> google.protobuf.Timestamp
+	32, // 42: currency.CandlesRequest.To:type_name -> google.protobuf.Timestamp
+	1,  // 43: currency.CandlesResponse.Interval:type_name -> currency.CandleInterval
+	25, // 44: currency.CandlesResponse.Candles:type_name -> currency.Candle
+	32, // 45: currency.Candle.Start:type_name -> google.protobuf.Timestamp
+	32, // 46: currency.SetOverrideRequest.ExpiresAt:type_name -> google.protobuf.Timestamp
+	32, // 47: currency.Override.ExpiresAt:type_name -> google.protobuf.Timestamp
+	32, // 48: currency.Override.CreatedAt:type_name -> google.protobuf.Timestamp
+	27, // 49: currency.ListOverridesResponse.Overrides:type_name -> currency.Override
+	4,  // 50: currency.Currency.GetRate:input_type -> currency.RateRequest
+	6,  // 51: currency.Currency.SubscribeRates:input_type -> currency.SubscriptionRequest
+	30, // 52: currency.Currency.ListCurrencies:input_type -> currency.Empty
+	13, // 53: currency.Currency.GetHistoricalRate:input_type -> currency.HistoricalRateRequest
+	15, // 54: currency.Currency.GetRateSeries:input_type -> currency.RateSeriesRequest
+	18, // 55: currency.Currency.Convert:input_type -> currency.ConvertRequest
+	20, // 56: currency.Currency.GetRates:input_type -> currency.RatesRequest
+	23, // 57: currency.Currency.GetCandles:input_type -> currency.CandlesRequest
+	26, // 58: currency.Admin.SetOverride:input_type -> currency.SetOverrideRequest
+	30, // 59: currency.Admin.ListOverrides:input_type -> currency.Empty
+	29, // 60: currency.Admin.ClearOverride:input_type -> currency.ClearOverrideRequest
+	5,  // 61: currency.Currency.GetRate:output_type -> currency.RateResponse
+	12, // 62: currency.Currency.SubscribeRates:output_type -> currency.StreamingRateResponse
+	31, // 63: currency.Currency.ListCurrencies:output_type -> currency.ListCurrenciesResponse
+	14, // 64: currency.Currency.GetHistoricalRate:output_type -> currency.HistoricalRateResponse
+	16, // 65: currency.Currency.GetRateSeries:output_type -> currency.RateSeriesResponse
+	19, // 66: currency.Currency.Convert:output_type -> currency.ConvertResponse
+	21, // 67: currency.Currency.GetRates:output_type -> currency.RatesResponse
+	24, // 68: currency.Currency.GetCandles:output_type -> currency.CandlesResponse
+	27, // 69: currency.Admin.SetOverride:output_type -> currency.Override
+	28, // 70: currency.Admin.ListOverrides:output_type -> currency.ListOverridesResponse
+	30, // 71: currency.Admin.ClearOverride:output_type -> currency.Empty
+	61, // [61:72] is the sub-list for method output_type
+	50, // [50:61] is the sub-list for method input_type
+	50, // [50:50] is the sub-list for extension type_name
+	50, // [50:50] is the sub-list for extension extendee
+	0,  // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_currency_proto_init() }
@@ -572,18 +3175,30 @@ func file_currency_proto_init() {
 		return
 	}
 	file_currency_proto_msgTypes[2].OneofWrappers = []any{
+		(*SubscriptionRequest_Subscribe)(nil),
+		(*SubscriptionRequest_Unsubscribe)(nil),
+		(*SubscriptionRequest_Replace)(nil),
+		(*SubscriptionRequest_Heartbeat)(nil),
+		(*SubscriptionRequest_Resume)(nil),
+	}
+	file_currency_proto_msgTypes[8].OneofWrappers = []any{
 		(*StreamingRateResponse_RateResponse)(nil),
 		(*StreamingRateResponse_Error)(nil),
+		(*StreamingRateResponse_Ack)(nil),
+	}
+	file_currency_proto_msgTypes[18].OneofWrappers = []any{
+		(*RateResult_RateResponse)(nil),
+		(*RateResult_Error)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_currency_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   5,
+			NumEnums:      4,
+			NumMessages:   28,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_currency_proto_goTypes,
 		DependencyIndexes: file_currency_proto_depIdxs,
@@ -19,9 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Currency_GetRate_FullMethodName        = "/currency.Currency/GetRate"
-	Currency_SubscribeRates_FullMethodName = "/currency.Currency/SubscribeRates"
-	Currency_ListCurrencies_FullMethodName = "/currency.Currency/ListCurrencies"
+	Currency_GetRate_FullMethodName           = "/currency.Currency/GetRate"
+	Currency_SubscribeRates_FullMethodName    = "/currency.Currency/SubscribeRates"
+	Currency_ListCurrencies_FullMethodName    = "/currency.Currency/ListCurrencies"
+	Currency_GetHistoricalRate_FullMethodName = "/currency.Currency/GetHistoricalRate"
+	Currency_GetRateSeries_FullMethodName     = "/currency.Currency/GetRateSeries"
+	Currency_Convert_FullMethodName           = "/currency.Currency/Convert"
+	Currency_GetRates_FullMethodName          = "/currency.Currency/GetRates"
+	Currency_GetCandles_FullMethodName        = "/currency.Currency/GetCandles"
 )
 
 // CurrencyClient is the client API for Currency service.
@@ -31,10 +36,25 @@ type CurrencyClient interface {
 	// GetRate returns the exchange rate for the two provided currency codes
 	GetRate(ctx context.Context, in *RateRequest, opts ...grpc.CallOption) (*RateResponse, error)
 	// SubscribeRates allows a client to subscribe for changes in an exchange rate
-	// when the rate changes a response will be sent
-	SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RateRequest, StreamingRateResponse], error)
+	// when the rate changes a response will be sent. Clients can subscribe, unsubscribe,
+	// replace their subscriptions and send heartbeats over the same stream, every
+	// operation is acknowledged.
+	SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubscriptionRequest, StreamingRateResponse], error)
 	// ListCurrencies lists all available currencies
 	ListCurrencies(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListCurrenciesResponse, error)
+	// GetHistoricalRate returns the exchange rate that was in effect on the given date
+	GetHistoricalRate(ctx context.Context, in *HistoricalRateRequest, opts ...grpc.CallOption) (*HistoricalRateResponse, error)
+	// GetRateSeries returns the daily exchange rates between two dates
+	GetRateSeries(ctx context.Context, in *RateSeriesRequest, opts ...grpc.CallOption) (*RateSeriesResponse, error)
+	// Convert converts an amount between the two provided currencies, the result is
+	// rounded to the minor units of the destination currency
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+	// GetRates returns the exchange rates for many currency pairs at once, all rates
+	// are taken from the same snapshot of the server's rates
+	GetRates(ctx context.Context, in *RatesRequest, opts ...grpc.CallOption) (*RatesResponse, error)
+	// GetCandles returns the open, high, low and close rates of a pair in buckets of
+	// the requested interval, built from the rates the server published recently
+	GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesResponse, error)
 }
 
 type currencyClient struct {
@@ -55,18 +75,18 @@ func (c *currencyClient) GetRate(ctx context.Context, in *RateRequest, opts ...g
 	return out, nil
 }
 
-func (c *currencyClient) SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RateRequest, StreamingRateResponse], error) {
+func (c *currencyClient) SubscribeRates(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SubscriptionRequest, StreamingRateResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Currency_ServiceDesc.Streams[0], Currency_SubscribeRates_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[RateRequest, StreamingRateResponse]{ClientStream: stream}
+	x := &grpc.GenericClientStream[SubscriptionRequest, StreamingRateResponse]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Currency_SubscribeRatesClient = grpc.BidiStreamingClient[RateRequest, StreamingRateResponse]
+type Currency_SubscribeRatesClient = grpc.BidiStreamingClient[SubscriptionRequest, StreamingRateResponse]
 
 func (c *currencyClient) ListCurrencies(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListCurrenciesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
@@ -78,6 +98,56 @@ func (c *currencyClient) ListCurrencies(ctx context.Context, in *Empty, opts ...
 	return out, nil
 }
 
+func (c *currencyClient) GetHistoricalRate(ctx context.Context, in *HistoricalRateRequest, opts ...grpc.CallOption) (*HistoricalRateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoricalRateResponse)
+	err := c.cc.Invoke(ctx, Currency_GetHistoricalRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) GetRateSeries(ctx context.Context, in *RateSeriesRequest, opts ...grpc.CallOption) (*RateSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateSeriesResponse)
+	err := c.cc.Invoke(ctx, Currency_GetRateSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, Currency_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) GetRates(ctx context.Context, in *RatesRequest, opts ...grpc.CallOption) (*RatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RatesResponse)
+	err := c.cc.Invoke(ctx, Currency_GetRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyClient) GetCandles(ctx context.Context, in *CandlesRequest, opts ...grpc.CallOption) (*CandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CandlesResponse)
+	err := c.cc.Invoke(ctx, Currency_GetCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CurrencyServer is the server API for Currency service.
 // All implementations must embed UnimplementedCurrencyServer
 // for forward compatibility.
@@ -85,10 +155,25 @@ type CurrencyServer interface {
 	// GetRate returns the exchange rate for the two provided currency codes
 	GetRate(context.Context, *RateRequest) (*RateResponse, error)
 	// SubscribeRates allows a client to subscribe for changes in an exchange rate
-	// when the rate changes a response will be sent
-	SubscribeRates(grpc.BidiStreamingServer[RateRequest, StreamingRateResponse]) error
+	// when the rate changes a response will be sent. Clients can subscribe, unsubscribe,
+	// replace their subscriptions and send heartbeats over the same stream, every
+	// operation is acknowledged.
+	SubscribeRates(grpc.BidiStreamingServer[SubscriptionRequest, StreamingRateResponse]) error
 	// ListCurrencies lists all available currencies
 	ListCurrencies(context.Context, *Empty) (*ListCurrenciesResponse, error)
+	// GetHistoricalRate returns the exchange rate that was in effect on the given date
+	GetHistoricalRate(context.Context, *HistoricalRateRequest) (*HistoricalRateResponse, error)
+	// GetRateSeries returns the daily exchange rates between two dates
+	GetRateSeries(context.Context, *RateSeriesRequest) (*RateSeriesResponse, error)
+	// Convert converts an amount between the two provided currencies, the result is
+	// rounded to the minor units of the destination currency
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	// GetRates returns the exchange rates for many currency pairs at once, all rates
+	// are taken from the same snapshot of the server's rates
+	GetRates(context.Context, *RatesRequest) (*RatesResponse, error)
+	// GetCandles returns the open, high, low and close rates of a pair in buckets of
+	// the requested interval, built from the rates the server published recently
+	GetCandles(context.Context, *CandlesRequest) (*CandlesResponse, error)
 	mustEmbedUnimplementedCurrencyServer()
 }
 
@@ -102,12 +187,27 @@ type UnimplementedCurrencyServer struct{}
 func (UnimplementedCurrencyServer) GetRate(context.Context, *RateRequest) (*RateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRate not implemented")
 }
-func (UnimplementedCurrencyServer) SubscribeRates(grpc.BidiStreamingServer[RateRequest, StreamingRateResponse]) error {
+func (UnimplementedCurrencyServer) SubscribeRates(grpc.BidiStreamingServer[SubscriptionRequest, StreamingRateResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeRates not implemented")
 }
 func (UnimplementedCurrencyServer) ListCurrencies(context.Context, *Empty) (*ListCurrenciesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCurrencies not implemented")
 }
+func (UnimplementedCurrencyServer) GetHistoricalRate(context.Context, *HistoricalRateRequest) (*HistoricalRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistoricalRate not implemented")
+}
+func (UnimplementedCurrencyServer) GetRateSeries(context.Context, *RateSeriesRequest) (*RateSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRateSeries not implemented")
+}
+func (UnimplementedCurrencyServer) Convert(context.Context, *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedCurrencyServer) GetRates(context.Context, *RatesRequest) (*RatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRates not implemented")
+}
+func (UnimplementedCurrencyServer) GetCandles(context.Context, *CandlesRequest) (*CandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandles not implemented")
+}
 func (UnimplementedCurrencyServer) mustEmbedUnimplementedCurrencyServer() {}
 func (UnimplementedCurrencyServer) testEmbeddedByValue()                  {}
 
@@ -148,11 +248,11 @@ func _Currency_GetRate_Handler(srv interface{}, ctx context.Context, dec func(in
 }
 
 func _Currency_SubscribeRates_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(CurrencyServer).SubscribeRates(&grpc.GenericServerStream[RateRequest, StreamingRateResponse]{ServerStream: stream})
+	return srv.(CurrencyServer).SubscribeRates(&grpc.GenericServerStream[SubscriptionRequest, StreamingRateResponse]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type Currency_SubscribeRatesServer = grpc.BidiStreamingServer[RateRequest, StreamingRateResponse]
+type Currency_SubscribeRatesServer = grpc.BidiStreamingServer[SubscriptionRequest, StreamingRateResponse]
 
 func _Currency_ListCurrencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
@@ -172,6 +272,96 @@ func _Currency_ListCurrencies_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Currency_GetHistoricalRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoricalRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetHistoricalRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetHistoricalRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetHistoricalRate(ctx, req.(*HistoricalRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_GetRateSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetRateSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetRateSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetRateSeries(ctx, req.(*RateSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_GetRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetRates(ctx, req.(*RatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Currency_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Currency_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServer).GetCandles(ctx, req.(*CandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Currency_ServiceDesc is the grpc.ServiceDesc for Currency service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -187,6 +377,26 @@ var Currency_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListCurrencies",
 			Handler:    _Currency_ListCurrencies_Handler,
 		},
+		{
+			MethodName: "GetHistoricalRate",
+			Handler:    _Currency_GetHistoricalRate_Handler,
+		},
+		{
+			MethodName: "GetRateSeries",
+			Handler:    _Currency_GetRateSeries_Handler,
+		},
+		{
+			MethodName: "Convert",
+			Handler:    _Currency_Convert_Handler,
+		},
+		{
+			MethodName: "GetRates",
+			Handler:    _Currency_GetRates_Handler,
+		},
+		{
+			MethodName: "GetCandles",
+			Handler:    _Currency_GetCandles_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -198,3 +408,195 @@ var Currency_ServiceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "currency.proto",
 }
+
+const (
+	Admin_SetOverride_FullMethodName   = "/currency.Admin/SetOverride"
+	Admin_ListOverrides_FullMethodName = "/currency.Admin/ListOverrides"
+	Admin_ClearOverride_FullMethodName = "/currency.Admin/ClearOverride"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admin lets operators pin the rates of single pairs, only clients marked as
+// admin in the credentials file may call it
+type AdminClient interface {
+	// SetOverride pins the rate of a pair until it is cleared or expires, an
+	// existing override of the pair is replaced
+	SetOverride(ctx context.Context, in *SetOverrideRequest, opts ...grpc.CallOption) (*Override, error)
+	// ListOverrides returns the active overrides
+	ListOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListOverridesResponse, error)
+	// ClearOverride removes the override of a pair
+	ClearOverride(ctx context.Context, in *ClearOverrideRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) SetOverride(ctx context.Context, in *SetOverrideRequest, opts ...grpc.CallOption) (*Override, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Override)
+	err := c.cc.Invoke(ctx, Admin_SetOverride_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListOverrides(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListOverridesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOverridesResponse)
+	err := c.cc.Invoke(ctx, Admin_ListOverrides_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ClearOverride(ctx context.Context, in *ClearOverrideRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, Admin_ClearOverride_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility.
+//
+// Admin lets operators pin the rates of single pairs, only clients marked as
+// admin in the credentials file may call it
+type AdminServer interface {
+	// SetOverride pins the rate of a pair until it is cleared or expires, an
+	// existing override of the pair is replaced
+	SetOverride(context.Context, *SetOverrideRequest) (*Override, error)
+	// ListOverrides returns the active overrides
+	ListOverrides(context.Context, *Empty) (*ListOverridesResponse, error)
+	// ClearOverride removes the override of a pair
+	ClearOverride(context.Context, *ClearOverrideRequest) (*Empty, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServer struct{}
+
+func (UnimplementedAdminServer) SetOverride(context.Context, *SetOverrideRequest) (*Override, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetOverride not implemented")
+}
+func (UnimplementedAdminServer) ListOverrides(context.Context, *Empty) (*ListOverridesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOverrides not implemented")
+}
+func (UnimplementedAdminServer) ClearOverride(context.Context, *ClearOverrideRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearOverride not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+func (UnimplementedAdminServer) testEmbeddedByValue()               {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_SetOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_SetOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetOverride(ctx, req.(*SetOverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListOverrides_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListOverrides(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListOverrides_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListOverrides(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ClearOverride_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearOverrideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ClearOverride(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ClearOverride_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ClearOverride(ctx, req.(*ClearOverrideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "currency.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetOverride",
+			Handler:    _Admin_SetOverride_Handler,
+		},
+		{
+			MethodName: "ListOverrides",
+			Handler:    _Admin_ListOverrides_Handler,
+		},
+		{
+			MethodName: "ClearOverride",
+			Handler:    _Admin_ClearOverride_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "currency.proto",
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Admin implements the Admin service, it lets operators pin the rates of single
+// pairs. Every RPC requires a client authenticated by the Authenticator and
+// marked as admin in the credentials file, without authentication the service
+// refuses all calls.
+type Admin struct {
+	log   hclog.Logger
+	rates *data.ExchangeRates
+	protos.UnimplementedAdminServer
+}
+
+// NewAdmin creates the Admin server for rates
+func NewAdmin(l hclog.Logger, r *data.ExchangeRates) *Admin {
+	return &Admin{log: l, rates: r}
+}
+
+// authorize returns the admin client calling ctx
+func (a *Admin) authorize(ctx context.Context) (Client, error) {
+	client, ok := ClientFromContext(ctx)
+	if !ok {
+		return Client{}, status.Error(codes.Unauthenticated, "Admin RPCs require client credentials")
+	}
+	if !client.Admin {
+		LoggerFromContext(ctx, a.log).Warn("Rejected admin RPC from a client which is not an admin")
+		return Client{}, status.Errorf(codes.PermissionDenied, "Client %s is not an admin", client.ID)
+	}
+	return client, nil
+}
+
+func (a *Admin) SetOverride(ctx context.Context, req *protos.SetOverrideRequest) (*protos.Override, error) {
+	client, err := a.authorize(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	base := currencyCode(req.GetBaseCode(), protos.Currencies_UNKNOWN)
+	dest := currencyCode(req.GetDestinationCode(), protos.Currencies_UNKNOWN)
+	LoggerFromContext(ctx, a.log).Info("Handle SetOverride", "base", base, "dest", dest, "rate", req.GetRate())
+
+	if errMsg := validateSupportedCodes(a.rates, base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	rate, err := data.ParseDecimal(req.GetRate())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid rate: %v", err)
+	}
+
+	o := data.Override{
+		Pair:      data.Pair{Base: base, Dest: dest},
+		Rate:      rate,
+		Reason:    req.GetReason(),
+		CreatedBy: client.ID,
+	}
+	if req.GetExpiresAt() != nil {
+		o.ExpiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	o, err = a.rates.SetOverride(o)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid override: %v", err)
+	}
+	return overrideResponse(o), nil
+}
+
+func (a *Admin) ListOverrides(ctx context.Context, _ *protos.Empty) (*protos.ListOverridesResponse, error) {
+	if _, err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	overrides := a.rates.Overrides()
+	resp := &protos.ListOverridesResponse{Overrides: make([]*protos.Override, len(overrides))}
+	for i, o := range overrides {
+		resp.Overrides[i] = overrideResponse(o)
+	}
+	return resp, nil
+}
+
+func (a *Admin) ClearOverride(ctx context.Context, req *protos.ClearOverrideRequest) (*protos.Empty, error) {
+	if _, err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	base := currencyCode(req.GetBaseCode(), protos.Currencies_UNKNOWN)
+	dest := currencyCode(req.GetDestinationCode(), protos.Currencies_UNKNOWN)
+	LoggerFromContext(ctx, a.log).Info("Handle ClearOverride", "base", base, "dest", dest)
+
+	if errMsg := validateCodeFormat(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	if err := a.rates.ClearOverride(data.Pair{Base: base, Dest: dest}); errors.Is(err, data.ErrNoOverride) {
+		return nil, status.Errorf(codes.NotFound, "No override is set for %s/%s", base, dest)
+	}
+	return &protos.Empty{}, nil
+}
+
+func overrideResponse(o data.Override) *protos.Override {
+	resp := &protos.Override{
+		BaseCode:        o.Base,
+		DestinationCode: o.Dest,
+		Rate:            o.Rate.String(),
+		Reason:          o.Reason,
+		CreatedBy:       o.CreatedBy,
+		CreatedAt:       timestamppb.New(o.CreatedAt),
+	}
+	if !o.ExpiresAt.IsZero() {
+		resp.ExpiresAt = timestamppb.New(o.ExpiresAt)
+	}
+	return resp
+}
+
+// overriddenCurrencies returns the codes of the currencies of the active overrides
+func overriddenCurrencies(overrides []data.Override) []string {
+	seen := map[string]bool{}
+	var overridden []string
+	for _, o := range overrides {
+		for _, code := range []string{o.Base, o.Dest} {
+			if !seen[code] {
+				seen[code] = true
+				overridden = append(overridden, code)
+			}
+		}
+	}
+	return overridden
+}
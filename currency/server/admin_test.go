@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newAdminClients serves the Currency and Admin services with authentication,
+// client "ops" with key-ops is an admin and client "a" with key-a is not
+func newAdminClients(t *testing.T) (protos.CurrencyClient, protos.AdminClient) {
+	t.Helper()
+
+	ratesOpts := data.DefaultRatesOptions()
+	ratesOpts.Monitor.Interval = time.Hour
+	rates, err := data.NewRates(hclog.NewNullLogger(), data.NewStaticProvider(data.DefaultStaticRates), ratesOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCurrency(hclog.NewNullLogger(), rates, data.NewHistoricalRates(hclog.NewNullLogger()), DefaultOptions())
+
+	auth := NewAuthenticator(hclog.NewNullLogger(), &Credentials{
+		Clients: []ClientCredentials{
+			{ID: "ops", KeyHashes: []string{HashKey("key-ops")}, Admin: true},
+			{ID: "a", KeyHashes: []string{HashKey("key-a")}},
+		},
+	})
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryLogging(hclog.NewNullLogger()), auth.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(StreamLogging(hclog.NewNullLogger()), auth.StreamInterceptor()))
+	protos.RegisterCurrencyServer(gs, c)
+	protos.RegisterAdminServer(gs, NewAdmin(hclog.NewNullLogger(), rates))
+	lis := bufconn.Listen(1 << 20)
+	go gs.Serve(lis)
+	t.Cleanup(func() {
+		gs.Stop()
+		c.Close()
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return protos.NewCurrencyClient(conn), protos.NewAdminClient(conn)
+}
+
+func TestAdminRequiresAdminClient(t *testing.T) {
+	_, admin := newAdminClients(t)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{"missing key", context.Background(), codes.Unauthenticated},
+		{"not an admin", withKey(APIKeyHeader, "key-a"), codes.PermissionDenied},
+		{"admin", withKey(APIKeyHeader, "key-ops"), codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := admin.ListOverrides(tt.ctx, &protos.Empty{})
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+
+	// without authentication there is no client to authorize
+	_, err := NewAdmin(hclog.NewNullLogger(), nil).ListOverrides(context.Background(), &protos.Empty{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without authentication, got %v", err)
+	}
+}
+
+func TestAdminOverrides(t *testing.T) {
+	client, admin := newAdminClients(t)
+	ops := withKey(APIKeyHeader, "key-ops")
+
+	invalid := []*protos.SetOverrideRequest{
+		{BaseCode: "EUR", DestinationCode: "USD", Rate: "abc"},
+		{BaseCode: "EUR", DestinationCode: "USD", Rate: "-1"},
+		{BaseCode: "EUR", DestinationCode: "XYZ", Rate: "1"},
+		{BaseCode: "EUR", DestinationCode: "USD", Rate: "1", ExpiresAt: timestamppb.New(time.Now().Add(-time.Minute))},
+	}
+	for _, req := range invalid {
+		if _, err := admin.SetOverride(ops, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", req, err)
+		}
+	}
+
+	o, err := admin.SetOverride(ops, &protos.SetOverrideRequest{
+		BaseCode: "eur", DestinationCode: "USD", Rate: "1.25", Reason: "support ticket",
+		ExpiresAt: timestamppb.New(time.Now().Add(time.Hour)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.GetBaseCode() != "EUR" || o.GetRate() != "1.25" || o.GetCreatedBy() != "ops" || o.GetExpiresAt() == nil {
+		t.Errorf("unexpected override %v", o)
+	}
+
+	user := withKey(APIKeyHeader, "key-a")
+	rate, err := client.GetRate(user, &protos.RateRequest{BaseCode: "USD", DestinationCode: "EUR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rate.GetOverridden() || rate.GetExactRate() != "0.8" {
+		t.Errorf("expected the inverse of the pinned rate marked as overridden, got %v", rate)
+	}
+	currencies, err := client.ListCurrencies(user, &protos.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := currencies.GetOverridden(); len(got) != 2 || got[0] != "EUR" || got[1] != "USD" {
+		t.Errorf("expected EUR and USD to be overridden, got %v", got)
+	}
+
+	list, err := admin.ListOverrides(ops, &protos.Empty{})
+	if err != nil || len(list.GetOverrides()) != 1 || list.GetOverrides()[0].GetReason() != "support ticket" {
+		t.Errorf("expected the override in the list, got %v, %v", list, err)
+	}
+
+	if _, err := admin.ClearOverride(ops, &protos.ClearOverrideRequest{BaseCode: "EUR", DestinationCode: "USD"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := admin.ClearOverride(ops, &protos.ClearOverrideRequest{BaseCode: "EUR", DestinationCode: "USD"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for a cleared override, got %v", err)
+	}
+	rate, err = client.GetRate(user, &protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD"})
+	if err != nil || rate.GetOverridden() {
+		t.Errorf("expected the rate of the source once cleared, got %v, %v", rate, err)
+	}
+}
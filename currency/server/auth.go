@@ -0,0 +1,347 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// APIKeyHeader is the metadata key carrying an API key
+	APIKeyHeader = "x-api-key"
+	// AuthorizationHeader carries the key as a bearer token, "Bearer <key>"
+	AuthorizationHeader = "authorization"
+)
+
+// unauthenticatedMethods can be called without credentials, load balancers and
+// orchestrators check the health of the service
+var unauthenticatedMethods = []string{"/grpc.health.v1.Health/"}
+
+// ClientLimits are the quotas of a client, a zero value means unlimited
+type ClientLimits struct {
+	// RequestsPerSecond is the sustained rate of RPCs, streams count once when opened
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	// Burst is the number of RPCs allowed at once above RequestsPerSecond
+	Burst int `json:"burst"`
+	// MaxStreams is the number of SubscribeRates streams open at the same time
+	MaxStreams int `json:"max_streams"`
+	// MaxPairsPerStream is the number of pairs a single stream can subscribe to
+	MaxPairsPerStream int `json:"max_pairs_per_stream"`
+}
+
+// ClientCredentials identifies a client by the SHA-256 hashes of its keys, more than
+// one key can be valid at a time so keys can be rotated without downtime
+type ClientCredentials struct {
+	ID        string        `json:"id"`
+	KeyHashes []string      `json:"key_hashes"`
+	Limits    *ClientLimits `json:"limits,omitempty"` // DefaultLimits when not set
+	// Admin allows the client to call the Admin service
+	Admin bool `json:"admin,omitempty"`
+}
+
+// Credentials is the local store of the clients allowed to call the service
+type Credentials struct {
+	DefaultLimits ClientLimits        `json:"default_limits"`
+	Clients       []ClientCredentials `json:"clients"`
+}
+
+// LoadCredentials reads and validates a JSON credentials file
+func LoadCredentials(path string) (*Credentials, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open credentials file: %w", err)
+	}
+	defer f.Close()
+
+	creds := &Credentials{}
+	d := json.NewDecoder(f)
+	d.DisallowUnknownFields()
+	if err := d.Decode(creds); err != nil {
+		return nil, fmt.Errorf("unable to parse credentials file %s: %w", path, err)
+	}
+	if err := creds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid credentials file %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// Validate checks that every client has a unique ID and at least one key, and
+// that no key is shared between clients
+func (c *Credentials) Validate() error {
+	var errs []error
+	ids := make(map[string]struct{}, len(c.Clients))
+	keys := make(map[string]string)
+	for i, client := range c.Clients {
+		if client.ID == "" {
+			errs = append(errs, fmt.Errorf("client %d has no id", i))
+			continue
+		}
+		if _, exists := ids[client.ID]; exists {
+			errs = append(errs, fmt.Errorf("client %q is defined more than once", client.ID))
+		}
+		ids[client.ID] = struct{}{}
+
+		if len(client.KeyHashes) == 0 {
+			errs = append(errs, fmt.Errorf("client %q has no key hashes", client.ID))
+		}
+		for _, h := range client.KeyHashes {
+			if b, err := hex.DecodeString(h); err != nil || len(b) != sha256.Size {
+				errs = append(errs, fmt.Errorf("client %q has a key hash which is not a hex encoded SHA-256", client.ID))
+				continue
+			}
+			h = strings.ToLower(h)
+			if other, exists := keys[h]; exists {
+				errs = append(errs, fmt.Errorf("clients %q and %q share a key", other, client.ID))
+			}
+			keys[h] = client.ID
+		}
+
+		if client.Limits != nil {
+			errs = append(errs, validateLimits(*client.Limits, "client "+client.ID))
+		}
+	}
+	errs = append(errs, validateLimits(c.DefaultLimits, "default limits"))
+	return errors.Join(errs...)
+}
+
+func validateLimits(l ClientLimits, name string) error {
+	if l.RequestsPerSecond < 0 || l.Burst < 0 || l.MaxStreams < 0 || l.MaxPairsPerStream < 0 {
+		return fmt.Errorf("%s cannot be negative", name)
+	}
+	return nil
+}
+
+// HashKey returns the hash of an API key as it is stored in the credentials file
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Client is the identity of an authenticated caller
+type Client struct {
+	ID     string
+	Limits ClientLimits
+	Admin  bool
+}
+
+type clientKey struct{}
+
+// ClientFromContext returns the client authenticated by the Authenticator, it
+// reports false when authentication is disabled
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	c, ok := ctx.Value(clientKey{}).(Client)
+	return c, ok
+}
+
+// clientState tracks the usage of a client across its connections
+type clientState struct {
+	limits  ClientLimits
+	admin   bool
+	limiter *rate.Limiter
+	streams int
+}
+
+// Authenticator checks the API key of every RPC against the credentials and
+// enforces the request rate and the concurrent streams of each client
+type Authenticator struct {
+	log hclog.Logger
+
+	mutex   sync.Mutex
+	keys    map[[sha256.Size]byte]string // key hash to client ID
+	clients map[string]*clientState
+}
+
+// NewAuthenticator creates an Authenticator for the clients in creds
+func NewAuthenticator(l hclog.Logger, creds *Credentials) *Authenticator {
+	a := &Authenticator{
+		log:     l,
+		clients: make(map[string]*clientState),
+	}
+	a.Reload(creds)
+	return a
+}
+
+// Reload replaces the credentials, the usage of clients that remain is kept so
+// open streams still count towards their limits
+func (a *Authenticator) Reload(creds *Credentials) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.keys = make(map[[sha256.Size]byte]string)
+	clients := make(map[string]*clientState, len(creds.Clients))
+	for _, client := range creds.Clients {
+		for _, h := range client.KeyHashes {
+			var hash [sha256.Size]byte
+			b, _ := hex.DecodeString(h) // validated by LoadCredentials
+			copy(hash[:], b)
+			a.keys[hash] = client.ID
+		}
+
+		limits := creds.DefaultLimits
+		if client.Limits != nil {
+			limits = *client.Limits
+		}
+
+		state, exists := a.clients[client.ID]
+		if exists {
+			state.limiter.SetLimit(requestLimit(limits))
+			state.limiter.SetBurst(requestBurst(limits))
+		} else {
+			// a new limiter starts with a full bucket
+			state = &clientState{limiter: rate.NewLimiter(requestLimit(limits), requestBurst(limits))}
+		}
+		state.limits = limits
+		state.admin = client.Admin
+		clients[client.ID] = state
+	}
+	a.clients = clients
+
+	a.log.Info("Loaded client credentials", "clients", len(clients))
+}
+
+func requestLimit(l ClientLimits) rate.Limit {
+	if l.RequestsPerSecond == 0 {
+		return rate.Inf
+	}
+	return rate.Limit(l.RequestsPerSecond)
+}
+
+func requestBurst(l ClientLimits) int {
+	if l.Burst == 0 {
+		// allow at least one request, otherwise nothing is ever allowed
+		return max(1, int(l.RequestsPerSecond))
+	}
+	return l.Burst
+}
+
+// UnaryInterceptor authenticates every unary RPC and applies the request rate
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isUnauthenticated(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		client, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(withClient(ctx, client), req)
+	}
+}
+
+// StreamInterceptor authenticates every streaming RPC and applies the request
+// rate and the limit on concurrent streams
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isUnauthenticated(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		client, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		if err := a.openStream(client); err != nil {
+			return err
+		}
+		defer a.closeStream(client)
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withClient(ss.Context(), client)})
+	}
+}
+
+// authenticate identifies the client from the metadata of ctx and takes one
+// request from its rate limit
+func (a *Authenticator) authenticate(ctx context.Context) (Client, error) {
+	key := requestKey(ctx)
+	if key == "" {
+		return Client{}, status.Error(codes.Unauthenticated, "Missing API key")
+	}
+	hash := sha256.Sum256([]byte(key))
+
+	a.mutex.Lock()
+	id, found := a.keys[hash]
+	state := a.clients[id]
+	a.mutex.Unlock()
+
+	if !found {
+		LoggerFromContext(ctx, a.log).Warn("Rejected invalid API key")
+		return Client{}, status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+
+	addLoggerFields(ctx, "client", id)
+
+	client := Client{ID: id, Limits: state.limits, Admin: state.admin}
+	if !state.limiter.Allow() {
+		return client, status.Errorf(codes.ResourceExhausted, "Request rate limit of %v per second exceeded", state.limits.RequestsPerSecond)
+	}
+	return client, nil
+}
+
+// openStream counts a new stream of client against its limit
+func (a *Authenticator) openStream(client Client) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	state, exists := a.clients[client.ID]
+	if !exists {
+		// removed by a reload since it was authenticated
+		return status.Error(codes.Unauthenticated, "Invalid API key")
+	}
+	if state.limits.MaxStreams > 0 && state.streams >= state.limits.MaxStreams {
+		return status.Errorf(codes.ResourceExhausted, "At most %d concurrent streams are allowed", state.limits.MaxStreams)
+	}
+	state.streams++
+	return nil
+}
+
+func (a *Authenticator) closeStream(client Client) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if state, exists := a.clients[client.ID]; exists && state.streams > 0 {
+		state.streams--
+	}
+}
+
+// requestKey returns the API key of the request, from x-api-key or a bearer token
+func requestKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if keys := md.Get(APIKeyHeader); len(keys) > 0 {
+		return keys[0]
+	}
+	for _, v := range md.Get(AuthorizationHeader) {
+		if token, ok := strings.CutPrefix(v, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+func isUnauthenticated(method string) bool {
+	for _, prefix := range unauthenticatedMethods {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func withClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newAuthClient(t *testing.T, limits ClientLimits) protos.CurrencyClient {
+	t.Helper()
+
+	auth := NewAuthenticator(hclog.NewNullLogger(), &Credentials{
+		DefaultLimits: limits,
+		Clients: []ClientCredentials{
+			{ID: "a", KeyHashes: []string{HashKey("key-a")}},
+			{ID: "b", KeyHashes: []string{HashKey("key-b")}},
+		},
+	})
+	return newTestClient(t,
+		grpc.ChainUnaryInterceptor(UnaryLogging(hclog.NewNullLogger()), auth.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(StreamLogging(hclog.NewNullLogger()), auth.StreamInterceptor()))
+}
+
+func withKey(header, value string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), header, value)
+}
+
+func TestAuthenticatorKeys(t *testing.T) {
+	client := newAuthClient(t, ClientLimits{})
+	req := &protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD"}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{"missing key", context.Background(), codes.Unauthenticated},
+		{"invalid key", withKey(APIKeyHeader, "nope"), codes.Unauthenticated},
+		{"api key", withKey(APIKeyHeader, "key-a"), codes.OK},
+		{"bearer token", withKey(AuthorizationHeader, "Bearer key-b"), codes.OK},
+		{"other scheme", withKey(AuthorizationHeader, "Basic key-b"), codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetRate(tt.ctx, req)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticatorRequestRate(t *testing.T) {
+	client := newAuthClient(t, ClientLimits{RequestsPerSecond: 0.001, Burst: 3})
+	req := &protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD"}
+
+	// the burst is available from the first request
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetRate(withKey(APIKeyHeader, "key-a"), req); err != nil {
+			t.Fatalf("request %d: expected success, got %v", i, err)
+		}
+	}
+	if _, err := client.GetRate(withKey(APIKeyHeader, "key-a"), req); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted once the burst is used, got %v", err)
+	}
+
+	// every client has its own limit
+	if _, err := client.GetRate(withKey(APIKeyHeader, "key-b"), req); err != nil {
+		t.Fatalf("expected success for another client, got %v", err)
+	}
+}
+
+func TestAuthenticatorStreamLimits(t *testing.T) {
+	client := newAuthClient(t, ClientLimits{MaxStreams: 1, MaxPairsPerStream: 2})
+
+	ctx, cancel := context.WithCancel(withKey(APIKeyHeader, "key-a"))
+	defer cancel()
+	stream, err := client.SubscribeRates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, dest := range []string{"USD", "GBP", "JPY"} {
+		err := stream.Send(&protos.SubscriptionRequest{
+			RequestId: uint64(i + 1),
+			Operation: &protos.SubscriptionRequest_Subscribe{
+				Subscribe: &protos.RateRequest{BaseCode: "EUR", DestinationCode: dest},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// skip rate updates until the response to this request
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.GetRateResponse() != nil {
+				continue
+			}
+			if i < 2 && msg.GetAck() == nil {
+				t.Fatalf("subscription %d: expected an ack, got %v", i, msg)
+			}
+			if i == 2 && codes.Code(msg.GetError().GetCode()) != codes.ResourceExhausted {
+				t.Fatalf("expected ResourceExhausted above the pair limit, got %v", msg)
+			}
+			break
+		}
+	}
+
+	// a second stream of the same client is over the limit
+	second, err := client.SubscribeRates(withKey(APIKeyHeader, "key-a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Recv(); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted for a second stream, got %v", err)
+	}
+
+	// another client is counted separately
+	other, err := client.SubscribeRates(withKey(APIKeyHeader, "key-b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Send(&protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Heartbeat{Heartbeat: &protos.Heartbeat{}}}); err != nil {
+		t.Fatal(err)
+	}
+	if msg, err := other.Recv(); err != nil || msg.GetAck() == nil {
+		t.Fatalf("expected an ack for another client, got %v %v", msg, err)
+	}
+}
+
+func TestLoadCredentialsValidation(t *testing.T) {
+	creds := &Credentials{Clients: []ClientCredentials{
+		{ID: "a", KeyHashes: []string{HashKey("shared")}},
+		{ID: "a", KeyHashes: []string{HashKey("shared")}},
+		{ID: "c", KeyHashes: []string{"not-a-hash"}},
+	}}
+	if err := creds.Validate(); err == nil {
+		t.Fatal("expected duplicate ids, shared keys and invalid hashes to be rejected")
+	}
+}
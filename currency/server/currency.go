@@ -2,41 +2,138 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/hashicorp/go-hclog"
 	"github.com/kahvecikaan/buildingMicroservices/currency/data"
 	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"io"
+	"math"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 )
 
+// isoCode matches the format of an ISO 4217 alphabetic currency code
+var isoCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
 // clientSubscription holds the subscription details for a client
 type clientSubscription struct {
-	rateRequests []*protos.RateRequest
+	pairs        []*pairSubscription
 	lastActivity time.Time
+	out          *outboundQueue // every message to the client goes through this queue
+	token        string         // resume token of the subscriptions
+	client       string         // ID of the authenticated client, empty without authentication
+}
+
+// pairSubscription is a single subscribed pair together with its push limits
+// and the last value pushed for it. The last pushed fields are only accessed
+// by the handleUpdates goroutine.
+type pairSubscription struct {
+	request      *protos.RateRequest
+	minChangeBps uint32
+	minInterval  time.Duration
+	maxSilence   time.Duration
+
+	lastRate data.Decimal
+	lastSent time.Time // zero until the first push
+	pending  bool      // a change was withheld by MinInterval
+}
+
+// shouldPush decides whether rate is pushed for the pair at now. Without limits
+// every update is pushed, otherwise a push needs MinInterval to have passed and
+// either the change to exceed MinChangeBps or MaxSilence to have expired. A change
+// withheld by MinInterval is marked pending so it is pushed once the interval has
+// passed, further updates may not arrive for a long time.
+func (p *pairSubscription) shouldPush(rate data.Decimal, now time.Time) bool {
+	if p.lastSent.IsZero() {
+		return true
+	}
+
+	elapsed := now.Sub(p.lastSent)
+	if p.minInterval > 0 && elapsed < p.minInterval {
+		p.pending = p.pending || p.exceedsMinChange(rate)
+		return false
+	}
+	p.pending = false
+
+	if p.maxSilence > 0 && elapsed >= p.maxSilence {
+		return true
+	}
+	return p.exceedsMinChange(rate)
+}
+
+// exceedsMinChange reports whether rate differs from the last pushed rate by at least MinChangeBps
+func (p *pairSubscription) exceedsMinChange(rate data.Decimal) bool {
+	last := p.lastRate.Float64()
+	if last == 0 {
+		return true
+	}
+	changeBps := math.Abs(rate.Float64()/last-1) * 10000
+	return changeBps >= float64(p.minChangeBps)
 }
 
 // Currency is a gRPC server that implements the methods defined by the CurrencyServer interface
 type Currency struct {
 	log           hclog.Logger
 	rates         *data.ExchangeRates
+	history       *data.HistoricalRates
 	subscriptions map[protos.Currency_SubscribeRatesServer]*clientSubscription
+	sessions      map[string]*detachedSession // subscriptions of closed streams by resume token
 	subsMutex     sync.RWMutex
+	opts          Options
+	counters      streamCounters
 	protos.UnimplementedCurrencyServer
 	closeCh chan struct{}
 	wg      sync.WaitGroup
 	once    sync.Once // Ensure Close() is called only once
 }
 
+// Options configures the Currency server
+type Options struct {
+	// Stream configures the outbound queue of every subscriber
+	Stream StreamOptions
+	// CleanupInterval is how often inactive subscriptions are looked for
+	CleanupInterval time.Duration
+	// StaleTimeout is how long a subscription can go without activity before it is removed
+	StaleTimeout time.Duration
+}
+
+// DefaultOptions returns the default stream options, a cleanup every minute
+// and a stale timeout of 5 minutes
+func DefaultOptions() Options {
+	return Options{
+		Stream:          DefaultStreamOptions(),
+		CleanupInterval: 1 * time.Minute,
+		StaleTimeout:    5 * time.Minute,
+	}
+}
+
 // NewCurrency creates a new Currency server
-func NewCurrency(l hclog.Logger, r *data.ExchangeRates) *Currency {
+func NewCurrency(l hclog.Logger, r *data.ExchangeRates, h *data.HistoricalRates, opts Options) *Currency {
+	defaults := DefaultOptions()
+	if opts.CleanupInterval <= 0 {
+		opts.CleanupInterval = defaults.CleanupInterval
+	}
+	if opts.StaleTimeout <= 0 {
+		opts.StaleTimeout = defaults.StaleTimeout
+	}
+	if opts.Stream.QueueSize <= 0 {
+		opts.Stream.QueueSize = defaults.Stream.QueueSize
+	}
+
 	c := &Currency{
 		log:           l,
 		rates:         r,
+		history:       h,
 		subscriptions: make(map[protos.Currency_SubscribeRatesServer]*clientSubscription),
+		sessions:      make(map[string]*detachedSession),
+		opts:          opts,
 		closeCh:       make(chan struct{}),
 	}
 	c.wg.Add(1)
@@ -48,54 +145,20 @@ func NewCurrency(l hclog.Logger, r *data.ExchangeRates) *Currency {
 // handleUpdates sends updated rates to subscribed clients and removes stale subscriptions
 func (c *Currency) handleUpdates() {
 	defer c.wg.Done()
-	rateUpdates := c.rates.MonitorRates(5 * time.Second)
-	cleanupTicker := time.NewTicker(1 * time.Minute)
+	rateUpdates := c.rates.MonitorRates()
+	cleanupTicker := time.NewTicker(c.opts.CleanupInterval)
 	defer cleanupTicker.Stop()
+	silenceTicker := time.NewTicker(1 * time.Second)
+	defer silenceTicker.Stop()
 
 	for {
 		select {
 		case <-rateUpdates:
 			c.log.Info("Got updated rates")
-			subsCopy := c.getSubscriptionsCopy()
-
-			// loop over subscribed clients
-			for clientStream, sub := range subsCopy {
-				// send updates to client
-				for _, rateRequest := range sub.rateRequests {
-					rate, err := c.rates.GetRate(rateRequest.GetBase().String(), rateRequest.GetDestination().String())
-					if err != nil {
-						c.log.Error(
-							"Unable to get updated rate",
-							"base", rateRequest.GetBase().String(),
-							"destination", rateRequest.GetDestination().String(),
-							"error", err,
-						)
-						continue
-					}
-
-					// send the updated rate to client
-					err = clientStream.Send(&protos.StreamingRateResponse{
-						Message: &protos.StreamingRateResponse_RateResponse{
-							RateResponse: &protos.RateResponse{
-								Base:        rateRequest.Base,
-								Destination: rateRequest.Destination,
-								Rate:        rate,
-							},
-						},
-					})
-
-					if err != nil {
-						c.log.Error(
-							"Unable to send updated rate to client, removing subscription",
-							"base", rateRequest.GetBase().String(),
-							"destination", rateRequest.GetDestination().String(),
-							"error", err,
-						)
-						c.removeSubscription(clientStream)
-						break // exit inner loop since client is removed
-					}
-				}
-			}
+			c.pushRates(false)
+		case <-silenceTicker.C:
+			// push pairs whose MaxSilence expired or whose change was held back by MinInterval
+			c.pushRates(true)
 		case <-cleanupTicker.C:
 			c.removeStaleSubscriptions()
 		case <-c.closeCh:
@@ -105,39 +168,133 @@ func (c *Currency) handleUpdates() {
 	}
 }
 
-// removeStaleSubscriptions removes subscriptions that have been inactive for more than 5 minutes
+// pushRates queues the current rate of every subscribed pair whose limits allow it,
+// when silentOnly is set only pairs whose MaxSilence has expired or with a pending
+// change are considered. Queueing never blocks, each stream is written by its own
+// SubscribeRates handler.
+func (c *Currency) pushRates(silentOnly bool) {
+	now := time.Now()
+	subsCopy := c.getSubscriptionsCopy()
+
+	// loop over subscribed clients
+	for _, sub := range subsCopy {
+		// queue updates for the client
+		for _, pair := range sub.pairs {
+			if silentOnly && !pair.pending && (pair.maxSilence == 0 || now.Sub(pair.lastSent) < pair.maxSilence) {
+				continue
+			}
+
+			rateRequest := pair.request
+			base, dest := rateRequest.GetBaseCode(), rateRequest.GetDestinationCode()
+			quote, err := c.rates.GetQuote(base, dest)
+			if err != nil {
+				c.log.Error(
+					"Unable to get updated rate",
+					"base", base,
+					"destination", dest,
+					"error", err,
+				)
+				continue
+			}
+
+			rate := quote.Rate
+			if !pair.shouldPush(rate, now) {
+				continue
+			}
+
+			// queue the updated rate for the client
+			sub.out.pushRate(base+"/"+dest, rateUpdate(rateRequest, quote, now))
+
+			pair.lastRate = rate
+			pair.lastSent = now
+		}
+	}
+}
+
+// removeStaleSubscriptions removes subscriptions that have been inactive for longer than
+// StaleTimeout and closed streams which can no longer be resumed
 func (c *Currency) removeStaleSubscriptions() {
 	c.subsMutex.Lock()
 	defer c.subsMutex.Unlock()
 
-	for clientStream, sub := range c.subscriptions {
-		if time.Since(sub.lastActivity) > 5*time.Minute {
+	c.removeExpiredSessions()
+
+	for _, sub := range c.subscriptions {
+		if len(sub.pairs) > 0 && time.Since(sub.lastActivity) > c.opts.StaleTimeout {
 			c.log.Info("Removing stale client subscription")
-			delete(c.subscriptions, clientStream)
+			sub.pairs = nil
+			c.counters.staleRemoved.Add(1)
 		}
 	}
 }
 
-// addSubscription adds a rate request to a client's subscription and updates last activity time
-func (c *Currency) addSubscription(clientStream protos.Currency_SubscribeRatesServer, rateRequest *protos.RateRequest) {
+// registerStream creates the empty subscription and outbound queue of a new client stream
+func (c *Currency) registerStream(clientStream protos.Currency_SubscribeRatesServer) *outboundQueue {
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	client, _ := ClientFromContext(clientStream.Context())
+	out := newOutboundQueue(c.opts.Stream, &c.counters)
+	c.subscriptions[clientStream] = &clientSubscription{
+		lastActivity: time.Now(),
+		out:          out,
+		token:        newResumeToken(),
+		client:       client.ID,
+	}
+	return out
+}
+
+// addSubscription adds a pair to a client's subscription and updates last activity time
+func (c *Currency) addSubscription(clientStream protos.Currency_SubscribeRatesServer, pair *pairSubscription) {
 	c.subsMutex.Lock()
 	defer c.subsMutex.Unlock()
 
 	sub, exists := c.subscriptions[clientStream]
 	if !exists {
-		sub = &clientSubscription{
-			rateRequests: []*protos.RateRequest{},
-		}
-		c.subscriptions[clientStream] = sub
+		return
 	}
-	sub.rateRequests = append(sub.rateRequests, rateRequest)
+	sub.pairs = append(sub.pairs, pair)
 	sub.lastActivity = time.Now()
 }
 
+// removePair removes a single pair from a client's subscription, it reports whether the pair was subscribed
+func (c *Currency) removePair(clientStream protos.Currency_SubscribeRatesServer, base, dest string) bool {
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	sub, exists := c.subscriptions[clientStream]
+	if !exists {
+		return false
+	}
+
+	for i, pair := range sub.pairs {
+		if pair.request.GetBaseCode() == base && pair.request.GetDestinationCode() == dest {
+			sub.pairs = append(sub.pairs[:i:i], sub.pairs[i+1:]...)
+			sub.lastActivity = time.Now()
+			return true
+		}
+	}
+	return false
+}
+
+// setSubscriptions replaces all pairs of a client's subscription and updates last activity time
+func (c *Currency) setSubscriptions(clientStream protos.Currency_SubscribeRatesServer, pairs []*pairSubscription) {
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	if sub, exists := c.subscriptions[clientStream]; exists {
+		sub.pairs = pairs
+		sub.lastActivity = time.Now()
+	}
+}
+
 func (c *Currency) removeSubscription(clientStream protos.Currency_SubscribeRatesServer) {
 	c.subsMutex.Lock()
 	defer c.subsMutex.Unlock()
-	delete(c.subscriptions, clientStream)
+	if sub, exists := c.subscriptions[clientStream]; exists {
+		c.detachSession(sub)
+		delete(c.subscriptions, clientStream)
+	}
 
 	// extract peer information
 	p, ok := peer.FromContext(clientStream.Context())
@@ -155,131 +312,340 @@ func (c *Currency) getSubscriptionsCopy() map[protos.Currency_SubscribeRatesServ
 
 	subsCopy := make(map[protos.Currency_SubscribeRatesServer]*clientSubscription)
 	for clientStream, sub := range c.subscriptions {
-		subsCopy[clientStream] = sub
+		// copy the pairs as well, they are modified by unsubscribe and replace
+		subsCopy[clientStream] = &clientSubscription{
+			pairs:        append([]*pairSubscription(nil), sub.pairs...),
+			lastActivity: sub.lastActivity,
+			out:          sub.out,
+		}
 	}
 	return subsCopy
 }
 
 func (c *Currency) GetRate(ctx context.Context, rr *protos.RateRequest) (*protos.RateResponse, error) {
-	c.log.Info("Handle request response for GetRate", "base", rr.GetBase(), "dest", rr.GetDestination())
-
-	// If base and destination are the same, return rate of 1.0
-	if rr.Base == rr.Destination {
-		return &protos.RateResponse{
-			Base:        rr.GetBase(),
-			Destination: rr.GetDestination(),
-			Rate:        1.0,
-		}, nil
-	}
-
-	// validate Base currency
-	if rr.GetBase() == protos.Currencies_UNKNOWN {
-		return nil, status.Errorf(codes.InvalidArgument, "Base currency is not specified")
-	}
+	base, dest := pairCodes(rr)
+	c.logger(ctx).Info("Handle request response for GetRate", "base", base, "dest", dest)
 
-	// validate Destination currency
-	if rr.GetDestination() == protos.Currencies_UNKNOWN {
-		return nil, status.Errorf(codes.InvalidArgument, "Destination currency is not specified")
+	// validate Base and Destination currencies
+	if errMsg := c.validateCodes(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
 	}
 
-	rate, err := c.rates.GetRate(rr.GetBase().String(), rr.GetDestination().String())
+	quote, err := c.rates.GetQuote(base, dest)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "Exchange rate not found")
 	}
 
+	rate := quote.Rate
+
 	return &protos.RateResponse{
-		Base:        rr.GetBase(),
-		Destination: rr.GetDestination(),
-		Rate:        rate,
+		Base:            currencyEnum(base),
+		Destination:     currencyEnum(dest),
+		Rate:            rate.Float64(),
+		ExactRate:       rate.String(),
+		BaseCode:        base,
+		DestinationCode: dest,
+		Sequence:        quote.Sequence,
+		AsOf:            timestamppb.New(quote.AsOf),
+		EmittedAt:       timestamppb.Now(),
+		Path:            quote.Route.Path,
+		Sources:         quote.Route.Sources,
+		Stale:           quote.Stale,
+		Overridden:      quote.Overridden,
 	}, nil
 }
 
-// SubscribeRates implements the rpc function specified in the .proto file
+// SubscribeRates implements the rpc function specified in the .proto file.
+// Requests are read on a separate goroutine while this one is the only writer
+// of the stream, draining the client's outbound queue.
 func (c *Currency) SubscribeRates(clientStream protos.Currency_SubscribeRatesServer) error {
+	out := c.registerStream(clientStream)
+	defer c.removeSubscription(clientStream)
+
+	recvDone := make(chan error, 1)
+	go func() {
+		// the recovery interceptor only covers the handler goroutine
+		defer func() {
+			if r := recover(); r != nil {
+				recvDone <- recovered(c.logger(clientStream.Context()), r)
+			}
+		}()
+		recvDone <- c.receiveRequests(clientStream, out)
+	}()
+
+	for {
+		select {
+		case <-out.ready:
+			if err := c.flush(clientStream, out); err != nil {
+				return err
+			}
+		case err := <-recvDone:
+			if err != nil {
+				return err
+			}
+			// deliver the responses to the last requests before closing the stream
+			return c.flush(clientStream, out)
+		case <-clientStream.Context().Done():
+			return clientStream.Context().Err()
+		}
+	}
+}
+
+// flush sends all queued messages to the client, it fails if the client
+// has to be disconnected or a send fails
+func (c *Currency) flush(clientStream protos.Currency_SubscribeRatesServer, out *outboundQueue) error {
+	msgs, overflowed := out.drain()
+	if overflowed {
+		stats := out.stats()
+		c.logger(clientStream.Context()).Warn("Client is too slow, disconnecting",
+			"dropped", stats.Dropped, "coalesced", stats.Coalesced)
+		return status.Error(codes.ResourceExhausted, "Outbound queue overflowed, client is too slow")
+	}
+
+	for _, msg := range msgs {
+		isUpdate := msg.GetRateResponse() != nil
+		if err := clientStream.Send(msg); err != nil {
+			if isUpdate {
+				c.counters.sendFailures.Add(1)
+			}
+			c.logger(clientStream.Context()).Error("Failed to send response", "error", err)
+			return status.Errorf(codes.Internal, "Failed to send response: %v", err)
+		}
+		if isUpdate {
+			c.counters.sent.Add(1)
+		}
+	}
+	return nil
+}
+
+// receiveRequests handles the requests of a client until the client closes the
+// stream, it returns nil on a clean close
+func (c *Currency) receiveRequests(clientStream protos.Currency_SubscribeRatesServer, out *outboundQueue) error {
 	for {
-		rateRequest, err := clientStream.Recv()
+		req, err := clientStream.Recv()
 		if err == io.EOF {
-			c.log.Info("Client has closed the connection")
-			c.removeSubscription(clientStream)
-			break
+			c.logger(clientStream.Context()).Info("Client has closed the connection")
+			return nil
 		}
 		if err != nil {
-			c.log.Error("Unable to read from client", "error", err)
-			c.removeSubscription(clientStream)
+			c.logger(clientStream.Context()).Error("Unable to read from client", "error", err)
 			return status.Errorf(codes.Internal, "Error receiving from client %v", err)
 		}
 
-		c.log.Info("Handle client request", "request_base", rateRequest.GetBase(), "request_dest", rateRequest.GetDestination())
-
-		if isHeartbeat(rateRequest) {
-			c.updateClientActivity(clientStream)
-		} else {
-			// validate the RateRequest
-			errMsg := c.validateRateRequest(rateRequest)
-			if errMsg != "" {
-				c.log.Error("Invalid RateRequest", "error", errMsg)
-
-				// create a google.rpc.Status error message
-				grpcError := status.New(codes.InvalidArgument, errMsg)
-				grpcErrorWithDetails, err := grpcError.WithDetails(rateRequest)
-				if err != nil {
-					c.log.Error("Failed to add details to error", "error", err)
-					// fallback to sending error without details
-					grpcErrorWithDetails = grpcError
-				}
-
-				// send error response within the stream
-				err = clientStream.Send(&protos.StreamingRateResponse{
-					Message: &protos.StreamingRateResponse_Error{
-						Error: grpcErrorWithDetails.Proto(),
-					},
-				})
-
-				if err != nil {
-					c.log.Error("Failed to send error response", "error", err)
-					c.removeSubscription(clientStream)
-					return status.Errorf(codes.Internal, "Failed to send error response: %v", err)
-				}
-				continue // skip adding the subscription
-			}
-
-			// check for duplicate subscription
-			if c.subscriptionExists(clientStream, rateRequest) {
-				errMsg := "Subscription already exists for this currency pair!"
-				c.log.Error(errMsg)
-
-				// create a google.rpc.Status error message
-				grpcError := status.New(codes.InvalidArgument, errMsg)
-				grpcErrorWithDetails, err := grpcError.WithDetails(rateRequest)
-				if err != nil {
-					c.log.Error("Failed to add details to error", "error", err)
-					// fallback to sending error without details
-					grpcErrorWithDetails = grpcError
-				}
-
-				// send error response within the stream
-				err = clientStream.Send(&protos.StreamingRateResponse{
-					Message: &protos.StreamingRateResponse_Error{
-						Error: grpcErrorWithDetails.Proto(),
-					},
-				})
-
-				if err != nil {
-					c.log.Error("Failed to send error response", "error", err)
-					c.removeSubscription(clientStream)
-					return status.Errorf(codes.Internal, "Failed to send error response %v", err)
-				}
+		var ack *protos.SubscriptionAck
+		switch op := req.GetOperation().(type) {
+		case *protos.SubscriptionRequest_Subscribe:
+			ack, err = c.subscribe(clientStream, op.Subscribe, req.GetOptions())
+		case *protos.SubscriptionRequest_Unsubscribe:
+			ack, err = c.unsubscribe(clientStream, op.Unsubscribe)
+		case *protos.SubscriptionRequest_Replace:
+			ack, err = c.replaceSubscriptions(clientStream, op.Replace.GetPairs(), req.GetOptions())
+		case *protos.SubscriptionRequest_Heartbeat:
+			ack = c.heartbeat(clientStream)
+		case *protos.SubscriptionRequest_Resume:
+			// the acknowledgement and the missed updates are queued by resume
+			if err = c.resume(clientStream, out, req.GetRequestId(), op.Resume); err == nil {
 				continue
 			}
+		default:
+			// a legacy RateRequest, it subscribes to a pair or is a heartbeat when empty
+			legacy := &protos.RateRequest{
+				Base:            req.GetBase(),
+				Destination:     req.GetDestination(),
+				BaseCode:        req.GetBaseCode(),
+				DestinationCode: req.GetDestinationCode(),
+			}
+			if isHeartbeat(pairCodes(legacy)) {
+				ack = c.heartbeat(clientStream)
+			} else {
+				ack, err = c.subscribe(clientStream, legacy, req.GetOptions())
+			}
+		}
 
-			c.addSubscription(clientStream, rateRequest)
+		if err != nil {
+			c.logger(clientStream.Context()).Error("Invalid subscription request", "error", err)
+			out.pushControl(streamingError(err, req))
+			continue
 		}
+
+		ack.RequestId = req.GetRequestId()
+		out.pushControl(&protos.StreamingRateResponse{
+			Message: &protos.StreamingRateResponse_Ack{Ack: ack},
+		})
+	}
+}
+
+// subscribe validates rr and adds it to the subscriptions of clientStream
+func (c *Currency) subscribe(
+	clientStream protos.Currency_SubscribeRatesServer,
+	rr *protos.RateRequest,
+	opts *protos.SubscriptionOptions) (*protos.SubscriptionAck, error) {
+	base, dest := pairCodes(rr)
+	c.logger(clientStream.Context()).Info("Handle subscribe", "request_base", base, "request_dest", dest)
+
+	pair, err := c.newPairSubscription(rr, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// check for duplicate subscription
+	if c.subscriptionExists(clientStream, pair.request) {
+		return nil, status.Error(codes.InvalidArgument, "Subscription already exists for this currency pair!")
+	}
+
+	if err := checkPairLimit(clientStream, c.pairCount(clientStream)+1); err != nil {
+		return nil, err
+	}
+
+	c.addSubscription(clientStream, pair)
+	return c.subscriptionAck(clientStream, protos.SubscriptionOperation_SUBSCRIBE), nil
+}
+
+// unsubscribe removes the pair in rr from the subscriptions of clientStream
+func (c *Currency) unsubscribe(clientStream protos.Currency_SubscribeRatesServer, rr *protos.RateRequest) (*protos.SubscriptionAck, error) {
+	base, dest := pairCodes(rr)
+	c.logger(clientStream.Context()).Info("Handle unsubscribe", "request_base", base, "request_dest", dest)
+
+	if !c.removePair(clientStream, base, dest) {
+		return nil, status.Error(codes.NotFound, "No subscription exists for this currency pair")
+	}
+
+	return c.subscriptionAck(clientStream, protos.SubscriptionOperation_UNSUBSCRIBE), nil
+}
+
+// replaceSubscriptions replaces all subscriptions of clientStream with pairs,
+// nothing is changed if any of the pairs is invalid
+func (c *Currency) replaceSubscriptions(
+	clientStream protos.Currency_SubscribeRatesServer,
+	rateRequests []*protos.RateRequest,
+	opts *protos.SubscriptionOptions) (*protos.SubscriptionAck, error) {
+	c.logger(clientStream.Context()).Info("Handle replace subscriptions", "pairs", len(rateRequests))
+
+	seen := make(map[string]struct{}, len(rateRequests))
+	pairs := make([]*pairSubscription, 0, len(rateRequests))
+	for _, rr := range rateRequests {
+		pair, err := c.newPairSubscription(rr, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		// ignore duplicates within the new set
+		key := pair.request.GetBaseCode() + "/" + pair.request.GetDestinationCode()
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		pairs = append(pairs, pair)
+	}
+
+	if err := checkPairLimit(clientStream, len(pairs)); err != nil {
+		return nil, err
+	}
+
+	c.setSubscriptions(clientStream, pairs)
+	return c.subscriptionAck(clientStream, protos.SubscriptionOperation_REPLACE), nil
+}
+
+// pairCount returns the number of pairs clientStream is subscribed to
+func (c *Currency) pairCount(clientStream protos.Currency_SubscribeRatesServer) int {
+	c.subsMutex.RLock()
+	defer c.subsMutex.RUnlock()
+
+	if sub, exists := c.subscriptions[clientStream]; exists {
+		return len(sub.pairs)
+	}
+	return 0
+}
+
+// checkPairLimit returns an error when n pairs exceed the limit of the client of clientStream
+func checkPairLimit(clientStream protos.Currency_SubscribeRatesServer, n int) error {
+	client, ok := ClientFromContext(clientStream.Context())
+	if !ok || client.Limits.MaxPairsPerStream == 0 || n <= client.Limits.MaxPairsPerStream {
+		return nil
+	}
+	return status.Errorf(codes.ResourceExhausted, "At most %d pairs can be subscribed per stream", client.Limits.MaxPairsPerStream)
+}
+
+// heartbeat records activity on clientStream
+func (c *Currency) heartbeat(clientStream protos.Currency_SubscribeRatesServer) *protos.SubscriptionAck {
+	c.updateClientActivity(clientStream)
+	return c.subscriptionAck(clientStream, protos.SubscriptionOperation_HEARTBEAT)
+}
+
+// normalizeRateRequest validates rr and returns a copy with both the enum and the code fields set
+func (c *Currency) normalizeRateRequest(rr *protos.RateRequest) (*protos.RateRequest, error) {
+	base, dest := pairCodes(rr)
+	if errMsg := c.validateRateRequest(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	return &protos.RateRequest{
+		Base:            currencyEnum(base),
+		Destination:     currencyEnum(dest),
+		BaseCode:        base,
+		DestinationCode: dest,
+	}, nil
+}
+
+// newPairSubscription validates rr and opts and creates the subscription for the pair
+func (c *Currency) newPairSubscription(rr *protos.RateRequest, opts *protos.SubscriptionOptions) (*pairSubscription, error) {
+	normalized, err := c.normalizeRateRequest(rr)
+	if err != nil {
+		return nil, err
+	}
+
+	pair := &pairSubscription{
+		request:      normalized,
+		minChangeBps: opts.GetMinChangeBps(),
+	}
+
+	if opts.GetMinInterval() != nil {
+		if err := opts.GetMinInterval().CheckValid(); err != nil || opts.GetMinInterval().AsDuration() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "MinInterval must be a positive duration")
+		}
+		pair.minInterval = opts.GetMinInterval().AsDuration()
+	}
+	if opts.GetMaxSilence() != nil {
+		if err := opts.GetMaxSilence().CheckValid(); err != nil || opts.GetMaxSilence().AsDuration() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "MaxSilence must be a positive duration")
+		}
+		pair.maxSilence = opts.GetMaxSilence().AsDuration()
+	}
+
+	return pair, nil
+}
+
+// subscriptionAck creates an acknowledgement listing the current subscriptions of clientStream
+func (c *Currency) subscriptionAck(clientStream protos.Currency_SubscribeRatesServer, op protos.SubscriptionOperation) *protos.SubscriptionAck {
+	c.subsMutex.RLock()
+	defer c.subsMutex.RUnlock()
+
+	ack := &protos.SubscriptionAck{Operation: op}
+	if sub, exists := c.subscriptions[clientStream]; exists {
+		ack.ResumeToken = sub.token
+		for _, pair := range sub.pairs {
+			ack.Subscriptions = append(ack.Subscriptions, pair.request)
+		}
+	}
+	return ack
+}
+
+// streamingError creates a google.rpc.Status error message for the stream with req attached as a detail
+func streamingError(err error, req *protos.SubscriptionRequest) *protos.StreamingRateResponse {
+	grpcError := status.Convert(err)
+	if grpcErrorWithDetails, err := grpcError.WithDetails(req); err == nil {
+		grpcError = grpcErrorWithDetails
+	}
+
+	return &protos.StreamingRateResponse{
+		Message: &protos.StreamingRateResponse_Error{
+			Error: grpcError.Proto(),
+		},
 	}
-	return nil
 }
 
 func (c *Currency) ListCurrencies(ctx context.Context, req *protos.Empty) (*protos.ListCurrenciesResponse, error) {
-	c.log.Info("Handling ListCurrencies request")
+	c.logger(ctx).Info("Handling ListCurrencies request")
 
 	// Utilize the thread-safe method from ExchangeRates
 	allRates := c.rates.GetAllRates()
@@ -292,9 +658,281 @@ func (c *Currency) ListCurrencies(ctx context.Context, req *protos.Empty) (*prot
 
 	return &protos.ListCurrenciesResponse{
 		Currencies: currencies,
+		Overridden: overriddenCurrencies(c.rates.Overrides()),
 	}, nil
 }
 
+func (c *Currency) GetHistoricalRate(ctx context.Context, req *protos.HistoricalRateRequest) (*protos.HistoricalRateResponse, error) {
+	base, dest := pairCodes(req)
+	c.logger(ctx).Info("Handle GetHistoricalRate", "base", base, "dest", dest, "date", req.GetDate())
+
+	// historical data may contain currencies that are no longer published, so only the format is checked
+	if errMsg := validateCodeFormat(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	date, err := time.Parse(data.DateLayout, req.GetDate())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Date must be formatted as YYYY-MM-DD")
+	}
+
+	dr, err := c.history.GetRate(base, dest, date)
+	if errors.Is(err, data.ErrAfterHistory) {
+		return nil, status.Errorf(codes.OutOfRange, "No rate is published for %s yet: %v", req.GetDate(), err)
+	}
+	if err != nil {
+		c.logger(ctx).Error("Unable to get historical rate", "error", err)
+		return nil, status.Errorf(codes.NotFound, "Historical exchange rate not found: %v", err)
+	}
+
+	return &protos.HistoricalRateResponse{
+		Base:            currencyEnum(base),
+		Destination:     currencyEnum(dest),
+		Rate:            dr.Rate.Float64(),
+		Date:            dr.Date.Format(data.DateLayout),
+		ExactRate:       dr.Rate.String(),
+		BaseCode:        base,
+		DestinationCode: dest,
+	}, nil
+}
+
+func (c *Currency) GetRateSeries(ctx context.Context, req *protos.RateSeriesRequest) (*protos.RateSeriesResponse, error) {
+	base, dest := pairCodes(req)
+	c.logger(ctx).Info("Handle GetRateSeries", "base", base, "dest", dest, "from", req.GetFrom(), "to", req.GetTo())
+
+	if errMsg := validateCodeFormat(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	from, err := time.Parse(data.DateLayout, req.GetFrom())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "From must be formatted as YYYY-MM-DD")
+	}
+	to, err := time.Parse(data.DateLayout, req.GetTo())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "To must be formatted as YYYY-MM-DD")
+	}
+	if to.Before(from) {
+		return nil, status.Errorf(codes.InvalidArgument, "To cannot be before From")
+	}
+
+	series, err := c.history.GetSeries(base, dest, from, to)
+	if err != nil {
+		c.logger(ctx).Error("Unable to get rate series", "error", err)
+		return nil, status.Errorf(codes.NotFound, "Historical exchange rates not found: %v", err)
+	}
+
+	rates := make([]*protos.DatedRate, 0, len(series))
+	for _, dr := range series {
+		rates = append(rates, &protos.DatedRate{
+			Date:      dr.Date.Format(data.DateLayout),
+			Rate:      dr.Rate.Float64(),
+			ExactRate: dr.Rate.String(),
+		})
+	}
+
+	return &protos.RateSeriesResponse{
+		Base:            currencyEnum(base),
+		Destination:     currencyEnum(dest),
+		Rates:           rates,
+		BaseCode:        base,
+		DestinationCode: dest,
+	}, nil
+}
+
+func (c *Currency) Convert(ctx context.Context, req *protos.ConvertRequest) (*protos.ConvertResponse, error) {
+	base, dest := pairCodes(req)
+	c.logger(ctx).Info("Handle Convert", "base", base, "dest", dest, "amount", req.GetAmount())
+
+	if errMsg := c.validateCodes(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+
+	// prefer the exact amount, the float amount is converted using its shortest representation
+	var amount data.Decimal
+	if req.GetExactAmount() != "" {
+		var err error
+		amount, err = data.ParseDecimal(req.GetExactAmount())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "ExactAmount is not a valid decimal: %v", err)
+		}
+	} else {
+		if math.IsNaN(req.GetAmount()) || math.IsInf(req.GetAmount(), 0) {
+			return nil, status.Error(codes.InvalidArgument, "Amount must be a finite number")
+		}
+		amount = data.DecimalFromFloat(req.GetAmount(), -1)
+	}
+
+	rate := data.NewDecimal(1)
+	if base != dest {
+		var err error
+		rate, err = c.rates.GetRate(base, dest)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "Exchange rate not found")
+		}
+	}
+
+	minorUnits := data.MinorUnits(dest)
+	converted, err := amount.Mul(rate).Round(minorUnits, data.RoundingMode(req.GetRoundingMode()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+
+	return &protos.ConvertResponse{
+		Base:            currencyEnum(base),
+		Destination:     currencyEnum(dest),
+		Amount:          converted.Float64(),
+		Rate:            rate.Float64(),
+		MinorUnits:      int32(minorUnits),
+		ExactAmount:     converted.StringFixed(minorUnits),
+		ExactRate:       rate.String(),
+		BaseCode:        base,
+		DestinationCode: dest,
+	}, nil
+}
+
+// maxBatchPairs limits the number of pairs a single GetRates call may request
+const maxBatchPairs = 1000
+
+func (c *Currency) GetRates(ctx context.Context, req *protos.RatesRequest) (*protos.RatesResponse, error) {
+	c.logger(ctx).Info("Handle GetRates", "base", req.GetBaseCode(), "destinations", len(req.GetDestinationCodes()), "pairs", len(req.GetPairs()))
+
+	// collect the requested pairs, DestinationCodes first followed by Pairs
+	requests := make([]*protos.RateRequest, 0, len(req.GetDestinationCodes())+len(req.GetPairs()))
+	for _, dest := range req.GetDestinationCodes() {
+		requests = append(requests, &protos.RateRequest{BaseCode: req.GetBaseCode(), DestinationCode: dest})
+	}
+	requests = append(requests, req.GetPairs()...)
+
+	if len(requests) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "No currency pairs requested")
+	}
+	if len(requests) > maxBatchPairs {
+		return nil, status.Errorf(codes.InvalidArgument, "At most %d currency pairs can be requested at once", maxBatchPairs)
+	}
+
+	// validate every pair up front, only the valid ones are priced
+	results := make([]*protos.RateResult, len(requests))
+	pairs := make([]data.Pair, 0, len(requests))
+	index := make([]int, 0, len(requests))
+	for i, rr := range requests {
+		base, dest := pairCodes(rr)
+		if errMsg := validateCodeFormat(base, dest); errMsg != "" {
+			results[i] = rateResultError(codes.InvalidArgument, errMsg, rr)
+			continue
+		}
+		pairs = append(pairs, data.Pair{Base: base, Dest: dest})
+		index = append(index, i)
+	}
+
+	// price all valid pairs from a single snapshot of the rates
+	now := timestamppb.Now()
+	for j, pr := range c.rates.GetRates(pairs) {
+		i := index[j]
+		if pr.Err != nil {
+			results[i] = rateResultError(codes.NotFound, "Exchange rate not found: "+pr.Err.Error(), requests[i])
+			continue
+		}
+
+		results[i] = &protos.RateResult{
+			Result: &protos.RateResult_RateResponse{
+				RateResponse: &protos.RateResponse{
+					Base:            currencyEnum(pr.Base),
+					Destination:     currencyEnum(pr.Dest),
+					Rate:            pr.Rate.Float64(),
+					ExactRate:       pr.Rate.String(),
+					BaseCode:        pr.Base,
+					DestinationCode: pr.Dest,
+					Sequence:        pr.Sequence,
+					AsOf:            timestamppb.New(pr.AsOf),
+					EmittedAt:       now,
+					Path:            pr.Route.Path,
+					Sources:         pr.Route.Sources,
+					Stale:           pr.Stale,
+					Overridden:      pr.Overridden,
+				},
+			},
+		}
+	}
+
+	return &protos.RatesResponse{Results: results}, nil
+}
+
+// rateResultError creates a failed RateResult with the request attached as a detail
+func rateResultError(code codes.Code, msg string, rr *protos.RateRequest) *protos.RateResult {
+	st := status.New(code, msg)
+	if withDetails, err := st.WithDetails(rr); err == nil {
+		st = withDetails
+	}
+
+	return &protos.RateResult{
+		Result: &protos.RateResult_Error{Error: st.Proto()},
+	}
+}
+
+// candleIntervals are the bucket lengths of the CandleInterval values
+var candleIntervals = map[protos.CandleInterval]time.Duration{
+	protos.CandleInterval_ONE_MINUTE:   time.Minute,
+	protos.CandleInterval_FIVE_MINUTES: 5 * time.Minute,
+	protos.CandleInterval_ONE_HOUR:     time.Hour,
+}
+
+// GetCandles returns the open, high, low and close rates of a pair in buckets of
+// the requested interval, computed from the rate series of the server
+func (c *Currency) GetCandles(ctx context.Context, req *protos.CandlesRequest) (*protos.CandlesResponse, error) {
+	base, dest := req.GetBaseCode(), req.GetDestinationCode()
+	c.logger(ctx).Info("Handle GetCandles", "base", base, "dest", dest, "interval", req.GetInterval().String())
+
+	if errMsg := c.validateCodes(base, dest); errMsg != "" {
+		return nil, status.Error(codes.InvalidArgument, errMsg)
+	}
+	interval, ok := candleIntervals[req.GetInterval()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Interval %v is not supported", req.GetInterval())
+	}
+
+	var from time.Time
+	if req.GetFrom() != nil {
+		from = req.GetFrom().AsTime()
+	}
+	to := time.Now()
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+	if !from.IsZero() && !to.After(from) {
+		return nil, status.Error(codes.InvalidArgument, "To must be after From")
+	}
+
+	candles, err := c.rates.Candles(base, dest, interval, from, to)
+	switch {
+	case errors.Is(err, data.ErrNoSeries):
+		return nil, status.Error(codes.FailedPrecondition, "Rate series are not recorded by this server")
+	case errors.Is(err, data.ErrTooManyCandles):
+		return nil, status.Errorf(codes.OutOfRange, "The requested range spans more than %d candles", data.MaxCandles)
+	case err != nil:
+		c.logger(ctx).Error("Unable to get candles", "error", err)
+		return nil, status.Errorf(codes.InvalidArgument, "Unable to get candles: %v", err)
+	}
+
+	resp := &protos.CandlesResponse{
+		BaseCode:        base,
+		DestinationCode: dest,
+		Interval:        req.GetInterval(),
+		Candles:         make([]*protos.Candle, 0, len(candles)),
+	}
+	for _, candle := range candles {
+		resp.Candles = append(resp.Candles, &protos.Candle{
+			Start:   timestamppb.New(candle.Start),
+			Open:    candle.Open,
+			High:    candle.High,
+			Low:     candle.Low,
+			Close:   candle.Close,
+			Samples: uint32(candle.Samples),
+		})
+	}
+	return resp, nil
+}
+
 // updateClientActivity updates the last activity timestamp for a client
 func (c *Currency) updateClientActivity(clientStream protos.Currency_SubscribeRatesServer) {
 	c.subsMutex.Lock()
@@ -306,20 +944,80 @@ func (c *Currency) updateClientActivity(clientStream protos.Currency_SubscribeRa
 }
 
 // isHeartbeat checks if the RateRequest is a heartbeat message
-func isHeartbeat(rr *protos.RateRequest) bool {
-	// Assuming that a RateRequest with UNKNOWN currencies is a heartbeat
-	return rr.GetBase() == protos.Currencies_UNKNOWN && rr.GetDestination() == protos.Currencies_UNKNOWN
+func isHeartbeat(base, dest string) bool {
+	// Assuming that a RateRequest without any currencies is a heartbeat
+	return base == "" && dest == ""
+}
+
+func (c *Currency) validateRateRequest(base, dest string) string {
+	if errMsg := c.validateCodes(base, dest); errMsg != "" {
+		return errMsg
+	}
+	if base == dest {
+		return "Base currency cannot be the same as destination currency"
+	}
+	return ""
 }
 
-func (c *Currency) validateRateRequest(rr *protos.RateRequest) string {
-	if rr.GetBase() == protos.Currencies_UNKNOWN {
+// currencyPair is implemented by every request message naming a base and destination currency
+type currencyPair interface {
+	GetBase() protos.Currencies
+	GetBaseCode() string
+	GetDestination() protos.Currencies
+	GetDestinationCode() string
+}
+
+// pairCodes returns the ISO 4217 codes of a request, the string codes take
+// precedence over the Currencies enum which is kept for older clients
+func pairCodes(p currencyPair) (string, string) {
+	return currencyCode(p.GetBaseCode(), p.GetBase()), currencyCode(p.GetDestinationCode(), p.GetDestination())
+}
+
+func currencyCode(code string, currency protos.Currencies) string {
+	if code != "" {
+		return strings.ToUpper(strings.TrimSpace(code))
+	}
+	if currency == protos.Currencies_UNKNOWN {
+		return ""
+	}
+	return currency.String()
+}
+
+// currencyEnum maps a code onto the Currencies enum, codes without an enum value map to UNKNOWN
+func currencyEnum(code string) protos.Currencies {
+	return protos.Currencies(protos.Currencies_value[code])
+}
+
+// validateCodeFormat checks both codes are specified and look like ISO 4217 codes
+func validateCodeFormat(base, dest string) string {
+	if base == "" {
 		return "Base currency is not specified"
 	}
-	if rr.GetDestination() == protos.Currencies_UNKNOWN {
+	if dest == "" {
 		return "Destination currency is not specified"
 	}
-	if rr.GetBase() == rr.GetDestination() {
-		return "Base currency cannot be the same as destination currency"
+	for _, code := range []string{base, dest} {
+		if !isoCode.MatchString(code) {
+			return fmt.Sprintf("Currency code %q is not a valid ISO 4217 code", code)
+		}
+	}
+	return ""
+}
+
+// validateCodes checks both codes are specified and supported by the loaded rates
+func (c *Currency) validateCodes(base, dest string) string {
+	return validateSupportedCodes(c.rates, base, dest)
+}
+
+// validateSupportedCodes checks both codes are specified and supported by rates
+func validateSupportedCodes(rates *data.ExchangeRates, base, dest string) string {
+	if errMsg := validateCodeFormat(base, dest); errMsg != "" {
+		return errMsg
+	}
+	for _, code := range []string{base, dest} {
+		if !rates.HasCurrency(code) {
+			return fmt.Sprintf("Currency %s is not supported", code)
+		}
 	}
 	return ""
 }
@@ -332,9 +1030,10 @@ func (c *Currency) subscriptionExists(
 	defer c.subsMutex.RUnlock()
 
 	if sub, exists := c.subscriptions[clientStream]; exists {
-		for _, existingRequest := range sub.rateRequests {
-			if existingRequest.GetBase() == rateRequest.GetBase() &&
-				existingRequest.GetDestination() == rateRequest.GetDestination() {
+		for _, pair := range sub.pairs {
+			existingRequest := pair.request
+			if existingRequest.GetBaseCode() == rateRequest.GetBaseCode() &&
+				existingRequest.GetDestinationCode() == rateRequest.GetDestinationCode() {
 				return true
 			}
 		}
@@ -343,6 +1042,37 @@ func (c *Currency) subscriptionExists(
 	return false
 }
 
+// StreamStats returns the totals about updates pushed to subscribers
+func (c *Currency) StreamStats() StreamStats {
+	return StreamStats{
+		Dropped:      c.counters.dropped.Load(),
+		Coalesced:    c.counters.coalesced.Load(),
+		Sent:         c.counters.sent.Load(),
+		SendFailures: c.counters.sendFailures.Load(),
+		StaleRemoved: c.counters.staleRemoved.Load(),
+	}
+}
+
+// SubscriptionStats returns the number of open streams and the number of
+// streams subscribed to each pair
+func (c *Currency) SubscriptionStats() (int, map[data.Pair]int) {
+	c.subsMutex.RLock()
+	defer c.subsMutex.RUnlock()
+
+	pairs := make(map[data.Pair]int)
+	for _, sub := range c.subscriptions {
+		for _, pair := range sub.pairs {
+			pairs[data.Pair{Base: pair.request.GetBaseCode(), Dest: pair.request.GetDestinationCode()}]++
+		}
+	}
+	return len(c.subscriptions), pairs
+}
+
+// logger returns the request scoped logger of ctx
+func (c *Currency) logger(ctx context.Context) hclog.Logger {
+	return LoggerFromContext(ctx, c.log)
+}
+
 // Close gracefully shuts down the Currency server
 func (c *Currency) Close() {
 	c.once.Do(func() {
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"context"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// newTestClient serves a Currency server with the static rates over an in memory
+// listener, the server is stopped when the test ends
+func newTestClient(t *testing.T, opts ...grpc.ServerOption) protos.CurrencyClient {
+	t.Helper()
+
+	ratesOpts := data.DefaultRatesOptions()
+	ratesOpts.Monitor.Interval = time.Hour
+	rates, err := data.NewRates(hclog.NewNullLogger(), data.NewStaticProvider(data.DefaultStaticRates), ratesOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCurrency(hclog.NewNullLogger(), rates, data.NewHistoricalRates(hclog.NewNullLogger()), DefaultOptions())
+	return serve(t, c, opts...)
+}
+
+// serve serves c over an in memory listener and returns a client connected to it,
+// the server and c are stopped when the test ends
+func serve(t *testing.T, c *Currency, opts ...grpc.ServerOption) protos.CurrencyClient {
+	t.Helper()
+
+	gs := grpc.NewServer(opts...)
+	protos.RegisterCurrencyServer(gs, c)
+	lis := bufconn.Listen(1 << 20)
+	go gs.Serve(lis)
+	t.Cleanup(func() {
+		gs.Stop()
+		c.Close()
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return protos.NewCurrencyClient(conn)
+}
+
+func TestConvertRejectsInvalidAmounts(t *testing.T) {
+	client := newTestClient(t)
+
+	tests := []struct {
+		name string
+		req  *protos.ConvertRequest
+		code codes.Code
+	}{
+		{"NaN", &protos.ConvertRequest{Amount: math.NaN()}, codes.InvalidArgument},
+		{"Inf", &protos.ConvertRequest{Amount: math.Inf(1)}, codes.InvalidArgument},
+		{"huge exponent", &protos.ConvertRequest{ExactAmount: "1e1000000"}, codes.InvalidArgument},
+		{"finite", &protos.ConvertRequest{Amount: 10}, codes.OK},
+		{"exact", &protos.ConvertRequest{ExactAmount: "1.5e2"}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Base, tt.req.Destination = protos.Currencies_EUR, protos.Currencies_USD
+			_, err := client.Convert(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestSubscriptionOperations(t *testing.T) {
+	client := newTestClient(t)
+	stream, err := client.SubscribeRates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { stream.CloseSend() })
+
+	pair := func(dest string) *protos.RateRequest {
+		return &protos.RateRequest{BaseCode: "EUR", DestinationCode: dest}
+	}
+	tests := []struct {
+		name string
+		req  *protos.SubscriptionRequest
+		code codes.Code
+		subs int
+	}{
+		{"subscribe", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Subscribe{Subscribe: pair("USD")}}, codes.OK, 1},
+		{"duplicate", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Subscribe{Subscribe: pair("USD")}}, codes.InvalidArgument, 0},
+		{"same currency", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Subscribe{Subscribe: &protos.RateRequest{BaseCode: "EUR", DestinationCode: "EUR"}}}, codes.InvalidArgument, 0},
+		{"replace", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Replace{Replace: &protos.ReplaceSubscriptions{
+			Pairs: []*protos.RateRequest{pair("GBP"), pair("JPY"), pair("GBP")},
+		}}}, codes.OK, 2},
+		{"invalid replace", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Replace{Replace: &protos.ReplaceSubscriptions{
+			Pairs: []*protos.RateRequest{pair("USD"), pair("XYZ")},
+		}}}, codes.InvalidArgument, 0},
+		{"unsubscribe", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Unsubscribe{Unsubscribe: pair("GBP")}}, codes.OK, 1},
+		{"unsubscribe unknown", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Unsubscribe{Unsubscribe: pair("USD")}}, codes.NotFound, 0},
+		{"heartbeat", &protos.SubscriptionRequest{Operation: &protos.SubscriptionRequest_Heartbeat{Heartbeat: &protos.Heartbeat{}}}, codes.OK, 1},
+	}
+
+	for i, tt := range tests {
+		tt.req.RequestId = uint64(i + 1)
+		if err := stream.Send(tt.req); err != nil {
+			t.Fatal(err)
+		}
+
+		// skip rate updates until the response to this request
+		var msg *protos.StreamingRateResponse
+		for msg == nil || msg.GetRateResponse() != nil {
+			if msg, err = stream.Recv(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if tt.code != codes.OK {
+			if code := codes.Code(msg.GetError().GetCode()); code != tt.code {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.code, msg)
+			}
+			continue
+		}
+		ack := msg.GetAck()
+		if ack == nil || ack.GetRequestId() != tt.req.RequestId || len(ack.GetSubscriptions()) != tt.subs {
+			t.Errorf("%s: expected an ack for request %d with %d subscriptions, got %v", tt.name, tt.req.RequestId, tt.subs, msg)
+		}
+	}
+}
+
+func TestShouldPush(t *testing.T) {
+	start := time.Now()
+	rate := func(s string) data.Decimal {
+		d, err := data.ParseDecimal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name     string
+		pair     pairSubscription
+		rate     string
+		after    time.Duration
+		expected bool
+		pending  bool
+	}{
+		{"first push", pairSubscription{minChangeBps: 100}, "1.0", 0, true, false},
+		{"no limits", pairSubscription{lastRate: rate("1.0"), lastSent: start}, "1.0", time.Millisecond, true, false},
+		{"below min change", pairSubscription{minChangeBps: 100, lastRate: rate("1.0"), lastSent: start}, "1.005", time.Second, false, false},
+		{"at min change", pairSubscription{minChangeBps: 100, lastRate: rate("1.0"), lastSent: start}, "1.01", time.Second, true, false},
+		{"negative change", pairSubscription{minChangeBps: 100, lastRate: rate("1.0"), lastSent: start}, "0.98", time.Second, true, false},
+		{"within min interval", pairSubscription{minInterval: time.Minute, lastRate: rate("1.0"), lastSent: start}, "1.1", time.Second, false, true},
+		{"small change within min interval", pairSubscription{minChangeBps: 100, minInterval: time.Minute, lastRate: rate("1.0"), lastSent: start}, "1.001", time.Second, false, false},
+		{"after min interval", pairSubscription{minInterval: time.Minute, lastRate: rate("1.0"), lastSent: start}, "1.1", time.Minute, true, false},
+		{"pending change reverted", pairSubscription{minChangeBps: 100, minInterval: time.Minute, lastRate: rate("1.0"), lastSent: start, pending: true}, "1.0", time.Minute, false, false},
+		{"max silence expired", pairSubscription{minChangeBps: 100, maxSilence: time.Minute, lastRate: rate("1.0"), lastSent: start}, "1.0", time.Minute, true, false},
+		{"max silence within min interval", pairSubscription{minInterval: 2 * time.Minute, maxSilence: time.Minute, lastRate: rate("1.0"), lastSent: start}, "1.0", time.Minute, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := tt.pair
+			if got := pair.shouldPush(rate(tt.rate), start.Add(tt.after)); got != tt.expected {
+				t.Errorf("expected shouldPush %v, got %v", tt.expected, got)
+			}
+			if pair.pending != tt.pending {
+				t.Errorf("expected pending %v, got %v", tt.pending, pair.pending)
+			}
+		})
+	}
+}
+
+func TestPendingChangeIsPushedAfterMinInterval(t *testing.T) {
+	start := time.Now()
+	one, _ := data.ParseDecimal("1.0")
+	moved, _ := data.ParseDecimal("1.05")
+	pair := &pairSubscription{minChangeBps: 100, minInterval: time.Minute, lastRate: one, lastSent: start}
+
+	// the change arrives inside the interval and no further update follows
+	if pair.shouldPush(moved, start.Add(time.Second)) {
+		t.Fatal("expected the change to be withheld within MinInterval")
+	}
+	if !pair.pending {
+		t.Fatal("expected the withheld change to be pending")
+	}
+
+	// the next silence tick after the interval pushes the same rate
+	if !pair.shouldPush(moved, start.Add(time.Minute)) {
+		t.Error("expected the pending change to be pushed once MinInterval passed")
+	}
+}
+
+func TestGetRatesSnapshot(t *testing.T) {
+	client := newTestClient(t)
+
+	resp, err := client.GetRates(context.Background(), &protos.RatesRequest{
+		BaseCode:         "EUR",
+		DestinationCodes: []string{"USD", "GBP", "XYZ"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sequence uint64
+	for i, result := range resp.GetResults()[:2] {
+		rr := result.GetRateResponse()
+		if rr == nil {
+			t.Fatalf("result %d: expected a rate, got %v", i, result)
+		}
+		if rr.GetSequence() == 0 || rr.GetAsOf() == nil || rr.GetEmittedAt() == nil {
+			t.Errorf("result %d: expected the snapshot details, got %v", i, rr)
+		}
+		if i > 0 && rr.GetSequence() != sequence {
+			t.Errorf("expected every pair priced from snapshot %d, got %d", sequence, rr.GetSequence())
+		}
+		sequence = rr.GetSequence()
+	}
+	if resp.GetResults()[2].GetError() == nil {
+		t.Error("expected an error for an unknown currency")
+	}
+}
+
+func TestGetRateRoute(t *testing.T) {
+	client := newTestClient(t)
+
+	rr, err := client.GetRate(context.Background(), &protos.RateRequest{BaseCode: "GBP", DestinationCode: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := rr.GetPath(); len(path) != 3 || path[0] != "GBP" || path[1] != "EUR" || path[2] != "USD" {
+		t.Errorf("expected GBP/USD through EUR, got %v", path)
+	}
+	if sources := rr.GetSources(); len(sources) != 1 || sources[0] != "default" {
+		t.Errorf("expected the rate of the only source, got %v", sources)
+	}
+}
+
+func TestGetCandles(t *testing.T) {
+	client := newTestClient(t)
+	now := time.Now()
+
+	resp, err := client.GetCandles(context.Background(), &protos.CandlesRequest{
+		BaseCode:        "EUR",
+		DestinationCode: "USD",
+		Interval:        protos.CandleInterval_ONE_HOUR,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the rates were loaded once and do not change during the test
+	if len(resp.GetCandles()) != 1 || resp.GetCandles()[0].GetSamples() != 1 {
+		t.Fatalf("expected a single candle of the initial rates, got %v", resp.GetCandles())
+	}
+	if c := resp.GetCandles()[0]; c.GetOpen() <= 0 || c.GetOpen() != c.GetClose() {
+		t.Errorf("expected the same open and close rate, got %v", c)
+	}
+
+	tests := []struct {
+		name string
+		req  *protos.CandlesRequest
+		code codes.Code
+	}{
+		{"unknown currency", &protos.CandlesRequest{BaseCode: "EUR", DestinationCode: "XYZ"}, codes.InvalidArgument},
+		{"unknown interval", &protos.CandlesRequest{BaseCode: "EUR", DestinationCode: "USD", Interval: 7}, codes.InvalidArgument},
+		{"to before from", &protos.CandlesRequest{
+			BaseCode: "EUR", DestinationCode: "USD",
+			From: timestamppb.New(now), To: timestamppb.New(now.Add(-time.Hour)),
+		}, codes.InvalidArgument},
+		{"too many candles", &protos.CandlesRequest{
+			BaseCode: "EUR", DestinationCode: "USD",
+			From: timestamppb.New(now.Add(-data.MaxCandles * time.Minute)),
+		}, codes.OutOfRange},
+		{"empty range", &protos.CandlesRequest{
+			BaseCode: "EUR", DestinationCode: "USD",
+			From: timestamppb.New(now.Add(time.Hour)), To: timestamppb.New(now.Add(2 * time.Hour)),
+		}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetCandles(context.Background(), tt.req)
+			if status.Code(err) != tt.code {
+				t.Errorf("expected %v, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestGetCandlesWithoutSeries(t *testing.T) {
+	ratesOpts := data.DefaultRatesOptions()
+	ratesOpts.Monitor.Interval = time.Hour
+	ratesOpts.SeriesSize = 0
+	rates, err := data.NewRates(hclog.NewNullLogger(), data.NewStaticProvider(data.DefaultStaticRates), ratesOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := serve(t, NewCurrency(hclog.NewNullLogger(), rates, data.NewHistoricalRates(hclog.NewNullLogger()), DefaultOptions()))
+
+	_, err = client.GetCandles(context.Background(), &protos.CandlesRequest{
+		BaseCode:        "EUR",
+		DestinationCode: "USD",
+		Interval:        protos.CandleInterval_ONE_HOUR,
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected %v, got %v", codes.FailedPrecondition, err)
+	}
+}
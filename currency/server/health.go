@@ -0,0 +1,125 @@
+package server
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"sync"
+	"time"
+)
+
+// HealthOptions configures how the state of the rates maps onto the health status
+type HealthOptions struct {
+	// MaxRateAge is how long after their last fetch from the provider the rates are
+	// still considered fit to serve, 0 never reports the service as NOT_SERVING
+	// because of old rates
+	MaxRateAge time.Duration
+	// CheckInterval is how often the rates are checked
+	CheckInterval time.Duration
+}
+
+// DefaultHealthOptions returns options that allow rates up to an hour old
+func DefaultHealthOptions() HealthOptions {
+	return HealthOptions{
+		MaxRateAge:    time.Hour,
+		CheckInterval: 5 * time.Second,
+	}
+}
+
+// Health implements grpc.health.v1.Health for the Currency service. Both the
+// overall status and the status of the currency.Currency service are NOT_SERVING
+// until rates are loaded, and again whenever the rates are older than MaxRateAge.
+// Simulated rates only age while they are derived from a snapshot, the simulation
+// never fetches again once the provider data was loaded.
+type Health struct {
+	*health.Server
+	log     hclog.Logger
+	rates   *data.ExchangeRates
+	opts    HealthOptions
+	status  healthpb.HealthCheckResponse_ServingStatus
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewHealth creates the health server and starts checking the rates
+func NewHealth(l hclog.Logger, r *data.ExchangeRates, opts HealthOptions) *Health {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = DefaultHealthOptions().CheckInterval
+	}
+
+	h := &Health{
+		Server:  health.NewServer(),
+		log:     l,
+		rates:   r,
+		opts:    opts,
+		closeCh: make(chan struct{}),
+	}
+	h.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	h.check()
+
+	h.wg.Add(1)
+	go h.monitor()
+
+	return h
+}
+
+// monitor re-evaluates the health status every CheckInterval
+func (h *Health) monitor() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(h.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.check()
+		case <-h.closeCh:
+			return
+		}
+	}
+}
+
+// check derives the serving status from the rates and publishes it when it changed
+func (h *Health) check() {
+	next := healthpb.HealthCheckResponse_SERVING
+	age := time.Since(h.rates.UpdatedAt())
+
+	switch {
+	case !h.rates.Ready():
+		next = healthpb.HealthCheckResponse_NOT_SERVING
+	case h.opts.MaxRateAge > 0 && age > h.opts.MaxRateAge && (h.rates.Mode() == data.MonitorLive || h.rates.Stale()):
+		next = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	if next == h.status {
+		return
+	}
+
+	if next == healthpb.HealthCheckResponse_SERVING {
+		h.log.Info("Rates are available, service is serving", "stale", h.rates.Stale())
+	} else {
+		h.log.Warn("Rates are unavailable or too old, service is not serving",
+			"age", age.Round(time.Second), "max_age", h.opts.MaxRateAge)
+	}
+	h.setStatus(next)
+}
+
+// setStatus publishes status for the overall server and the Currency service,
+// clients watching either of them are notified
+func (h *Health) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.status = status
+	h.SetServingStatus("", status)
+	h.SetServingStatus(protos.Currency_ServiceDesc.ServiceName, status)
+}
+
+// Close stops checking the rates and reports NOT_SERVING to all watchers
+func (h *Health) Close() {
+	h.once.Do(func() {
+		close(h.closeCh)
+		h.wg.Wait()
+		h.Shutdown()
+	})
+}
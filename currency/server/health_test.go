@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// downProvider always fails
+type downProvider struct{}
+
+func (downProvider) Rates() (data.RateSet, error) {
+	return data.RateSet{}, errors.New("provider unavailable")
+}
+
+// writeSnapshot writes a rate snapshot saved at savedAt to a temporary file
+func writeSnapshot(t *testing.T, savedAt time.Time) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	b, err := json.Marshal(map[string]any{
+		"as_of":    savedAt,
+		"rates":    map[string]string{"USD": "1.0892"},
+		"saved_at": savedAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHealthStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     data.MonitorMode
+		snapshot bool
+		expected healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"simulated from old snapshot", data.MonitorSimulate, true, healthpb.HealthCheckResponse_NOT_SERVING},
+		{"live from old snapshot", data.MonitorLive, true, healthpb.HealthCheckResponse_NOT_SERVING},
+		{"simulated from provider", data.MonitorSimulate, false, healthpb.HealthCheckResponse_SERVING},
+		{"live from provider", data.MonitorLive, false, healthpb.HealthCheckResponse_SERVING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := data.DefaultRatesOptions()
+			opts.Monitor.Mode = tt.mode
+			opts.Monitor.Interval = 5 * time.Millisecond
+			opts.RetryInterval = time.Hour
+
+			var provider data.RateProvider = data.NewStaticProvider(data.DefaultStaticRates)
+			if tt.snapshot {
+				provider = downProvider{}
+				opts.SnapshotPath = writeSnapshot(t, time.Now().Add(-2*time.Hour))
+			}
+
+			rates, err := data.NewRates(hclog.NewNullLogger(), provider, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rates.Close()
+			rates.MonitorRates()
+
+			// let the monitor tick a few times before checking
+			time.Sleep(20 * time.Millisecond)
+
+			h := NewHealth(hclog.NewNullLogger(), rates, HealthOptions{MaxRateAge: time.Hour, CheckInterval: time.Hour})
+			defer h.Close()
+
+			if h.status != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, h.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RequestIDHeader is the metadata key carrying the request ID, it is read from
+// incoming requests and returned in the response headers
+const RequestIDHeader = "x-request-id"
+
+type requestIDKey struct{}
+type loggerKey struct{}
+
+// RequestIDFromContext returns the request ID assigned by the logging interceptor
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// LoggerFromContext returns the request scoped logger set by the logging
+// interceptor, or fallback when the context has none
+func LoggerFromContext(ctx context.Context, fallback hclog.Logger) hclog.Logger {
+	if rl, ok := ctx.Value(loggerKey{}).(*requestLogger); ok {
+		return rl.get()
+	}
+	return fallback
+}
+
+// requestLogger is the logger of a request, interceptors running after the
+// logging interceptor can add fields which then also appear in its final log
+type requestLogger struct {
+	mutex sync.Mutex
+	l     hclog.Logger
+}
+
+func (rl *requestLogger) get() hclog.Logger {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	return rl.l
+}
+
+// addLoggerFields adds args to the request logger of ctx
+func addLoggerFields(ctx context.Context, args ...any) {
+	if rl, ok := ctx.Value(loggerKey{}).(*requestLogger); ok {
+		rl.mutex.Lock()
+		rl.l = rl.l.With(args...)
+		rl.mutex.Unlock()
+	}
+}
+
+// UnaryLogging assigns a request ID to every unary RPC and logs its outcome
+func UnaryLogging(log hclog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, rl := withRequestLogger(ctx, log, info.FullMethod)
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		logResult(rl.get(), start, err)
+		return resp, err
+	}
+}
+
+// StreamLogging assigns a request ID to every streaming RPC and logs its outcome
+func StreamLogging(log hclog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, rl := withRequestLogger(ss.Context(), log, info.FullMethod)
+		start := time.Now()
+		rl.get().Debug("Stream opened")
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+
+		logResult(rl.get(), start, err)
+		return err
+	}
+}
+
+// UnaryRecovery converts a panic in a unary handler into a codes.Internal error
+func UnaryRecovery(log hclog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(LoggerFromContext(ctx, log), r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery converts a panic in a stream handler into a codes.Internal error
+func StreamRecovery(log hclog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(LoggerFromContext(ss.Context(), log), r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs a recovered panic with its stack and returns the error for the client
+func recovered(log hclog.Logger, r any) error {
+	log.Error("Recovered from panic in handler", "panic", r, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "Internal server error")
+}
+
+// withRequestLogger reads the request ID from the incoming metadata or generates
+// one, returns it in the response headers and stores it and a logger carrying it
+// in the context
+func withRequestLogger(ctx context.Context, log hclog.Logger, method string) (context.Context, *requestLogger) {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDHeader); len(ids) > 0 {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		id = newRequestID()
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, id))
+
+	client := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		client = p.Addr.String()
+	}
+
+	rl := &requestLogger{l: log.With("request_id", id, "method", method, "peer", client)}
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	ctx = context.WithValue(ctx, loggerKey{}, rl)
+	return ctx, rl
+}
+
+// logResult logs the status code and duration of a finished RPC
+func logResult(l hclog.Logger, start time.Time, err error) {
+	code := status.Code(err)
+	duration := time.Since(start)
+
+	switch code {
+	case codes.OK, codes.Canceled:
+		l.Info("Request completed", "code", code.String(), "duration", duration)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		l.Error("Request failed", "code", code.String(), "duration", duration, "error", err)
+	default:
+		l.Warn("Request failed", "code", code.String(), "duration", duration, "error", err)
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
+
+// contextStream replaces the context of a grpc.ServerStream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
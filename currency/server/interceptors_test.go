@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestLoggingInterceptorRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	log := hclog.New(&hclog.LoggerOptions{Output: &logs, Level: hclog.Info})
+	client := newTestClient(t, grpc.ChainUnaryInterceptor(UnaryLogging(log)))
+	req := &protos.RateRequest{BaseCode: "EUR", DestinationCode: "USD"}
+
+	// a request ID sent by the client is kept
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDHeader, "abc123")
+	if _, err := client.GetRate(ctx, req, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if ids := header.Get(RequestIDHeader); len(ids) != 1 || ids[0] != "abc123" {
+		t.Errorf("expected the request ID abc123 in the response headers, got %v", ids)
+	}
+	if !strings.Contains(logs.String(), "request_id=abc123") {
+		t.Errorf("expected the request ID in the logs, got %s", logs.String())
+	}
+
+	// otherwise one is generated
+	header = nil
+	if _, err := client.GetRate(context.Background(), req, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if ids := header.Get(RequestIDHeader); len(ids) != 1 || len(ids[0]) != 16 {
+		t.Errorf("expected a generated request ID in the response headers, got %v", ids)
+	}
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	interceptor := UnaryRecovery(hclog.NewNullLogger())
+	panics := func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test"}, panics)
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+	if strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected the panic to stay out of the client error, got %v", err)
+	}
+}
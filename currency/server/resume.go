@@ -0,0 +1,192 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+const (
+	// sessionTTL is how long the subscriptions of a closed stream can be resumed
+	sessionTTL = 5 * time.Minute
+	// maxDetachedSessions bounds the number of closed streams kept for resuming
+	maxDetachedSessions = 10000
+)
+
+// detachedSession holds the subscriptions of a closed stream until it is resumed or expires
+type detachedSession struct {
+	pairs      []*pairSubscription
+	detachedAt time.Time
+	client     string // only the same client can resume the session
+}
+
+// newResumeToken returns a random token identifying the subscriptions of a stream
+func newResumeToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
+
+// detachSession keeps the subscriptions of a closed stream for resuming, evicting
+// the oldest session when there are too many. The caller must hold subsMutex.
+func (c *Currency) detachSession(sub *clientSubscription) {
+	if len(sub.pairs) == 0 {
+		return
+	}
+
+	if len(c.sessions) >= maxDetachedSessions {
+		var oldest string
+		for token, session := range c.sessions {
+			if oldest == "" || session.detachedAt.Before(c.sessions[oldest].detachedAt) {
+				oldest = token
+			}
+		}
+		delete(c.sessions, oldest)
+	}
+
+	c.sessions[sub.token] = &detachedSession{
+		pairs:      sub.pairs,
+		detachedAt: time.Now(),
+		client:     sub.client,
+	}
+}
+
+// removeExpiredSessions forgets closed streams that were not resumed in time.
+// The caller must hold subsMutex.
+func (c *Currency) removeExpiredSessions() {
+	for token, session := range c.sessions {
+		if time.Since(session.detachedAt) > sessionTTL {
+			delete(c.sessions, token)
+		}
+	}
+}
+
+// resume moves the subscriptions of a closed stream to clientStream and queues the
+// acknowledgement followed by the rate updates the client missed. Both are queued
+// before the pairs can receive new updates, so they always fit the outbound queue.
+func (c *Currency) resume(
+	clientStream protos.Currency_SubscribeRatesServer,
+	out *outboundQueue,
+	requestID uint64,
+	r *protos.Resume) error {
+	c.logger(clientStream.Context()).Info("Handle resume", "last_sequence", r.GetLastSequence())
+
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	session, found := c.sessions[r.GetToken()]
+	sub, exists := c.subscriptions[clientStream]
+	// the session of another client is treated as unknown
+	if !found || !exists || session.client != sub.client {
+		return status.Error(codes.NotFound, "Resume token is unknown or has expired")
+	}
+	// the limits of the client may have been lowered since the session was detached
+	if err := checkPairLimit(clientStream, len(session.pairs)); err != nil {
+		return err
+	}
+
+	// leave room for the acknowledgement and the messages already queued
+	replay, resync := c.replayUpdates(session.pairs, r.GetLastSequence(), out.free()-1)
+
+	delete(c.sessions, r.GetToken())
+	sub.pairs = session.pairs
+	sub.token = r.GetToken()
+	sub.lastActivity = time.Now()
+
+	ack := &protos.SubscriptionAck{
+		Operation:   protos.SubscriptionOperation_RESUME,
+		RequestId:   requestID,
+		ResumeToken: sub.token,
+		Resync:      resync,
+	}
+	for _, pair := range sub.pairs {
+		ack.Subscriptions = append(ack.Subscriptions, pair.request)
+	}
+
+	out.pushControl(&protos.StreamingRateResponse{
+		Message: &protos.StreamingRateResponse_Ack{Ack: ack},
+	})
+	for _, msg := range replay {
+		rr := msg.GetRateResponse()
+		out.pushRate(rr.GetBaseCode()+"/"+rr.GetDestinationCode(), msg)
+	}
+	return nil
+}
+
+// replayUpdates returns the updates of pairs published after lastSequence, only
+// the pairs which changed are included for every snapshot after the first. When
+// the updates are no longer available, or more than room of them are missing, the
+// current rate of every pair is returned instead and resync is true. Pairs whose
+// current rate does not fit into room either are left to the next silence tick.
+// The pairs must not be subscribed yet.
+func (c *Currency) replayUpdates(pairs []*pairSubscription, lastSequence uint64, room int) ([]*protos.StreamingRateResponse, bool) {
+	now := time.Now()
+
+	snapshots, ok := c.rates.SnapshotsSince(lastSequence)
+	var replay []*protos.StreamingRateResponse
+	if ok && lastSequence > 0 {
+		for i, snapshot := range snapshots {
+			for _, pair := range pairs {
+				base, dest := pair.request.GetBaseCode(), pair.request.GetDestinationCode()
+				quote, err := snapshot.Quote(base, dest)
+				if err != nil {
+					continue
+				}
+				if i > 0 {
+					if prev, err := snapshots[i-1].Rate(base, dest); err == nil && prev.Cmp(quote.Rate) == 0 {
+						continue
+					}
+				}
+				replay = append(replay, rateUpdate(pair.request, quote, now))
+			}
+		}
+
+		if len(replay) <= room {
+			return replay, false
+		}
+	}
+
+	replay = replay[:0]
+	for _, pair := range pairs {
+		if len(replay) >= room {
+			// pushed by the next silence tick once the queue has drained
+			pair.lastSent = time.Time{}
+			pair.pending = true
+			continue
+		}
+		quote, err := c.rates.GetQuote(pair.request.GetBaseCode(), pair.request.GetDestinationCode())
+		if err != nil {
+			continue
+		}
+		replay = append(replay, rateUpdate(pair.request, quote, now))
+	}
+	return replay, true
+}
+
+// rateUpdate creates the message pushing quote for the pair in rr
+func rateUpdate(rr *protos.RateRequest, quote data.Quote, emittedAt time.Time) *protos.StreamingRateResponse {
+	return &protos.StreamingRateResponse{
+		Message: &protos.StreamingRateResponse_RateResponse{
+			RateResponse: &protos.RateResponse{
+				Base:            rr.Base,
+				Destination:     rr.Destination,
+				Rate:            quote.Rate.Float64(),
+				ExactRate:       quote.Rate.String(),
+				BaseCode:        rr.GetBaseCode(),
+				DestinationCode: rr.GetDestinationCode(),
+				Sequence:        quote.Sequence,
+				AsOf:            timestamppb.New(quote.AsOf),
+				EmittedAt:       timestamppb.New(emittedAt),
+				Path:            quote.Route.Path,
+				Sources:         quote.Route.Sources,
+				Stale:           quote.Stale,
+				Overridden:      quote.Overridden,
+			},
+		},
+	}
+}
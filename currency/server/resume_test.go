@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/data"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+)
+
+// newResumeServer serves simulated rates that tick every millisecond until ticks
+// snapshots were published, the rates are frozen afterwards
+func newResumeServer(t *testing.T, stream StreamOptions, ticks uint64) (protos.CurrencyClient, *data.ExchangeRates) {
+	t.Helper()
+
+	opts := data.DefaultRatesOptions()
+	opts.Monitor.Interval = time.Millisecond
+	rates, err := data.NewRates(hclog.NewNullLogger(), data.NewStaticProvider(data.DefaultStaticRates), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srvOpts := DefaultOptions()
+	srvOpts.Stream = stream
+	c := NewCurrency(hclog.NewNullLogger(), rates, data.NewHistoricalRates(hclog.NewNullLogger()), srvOpts)
+	client := serve(t, c)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for rates.Sequence() < ticks {
+		if time.Now().After(deadline) {
+			t.Fatal("rates did not tick in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	rates.Reconfigure(data.MonitorOptions{Interval: time.Hour, Volatility: opts.Monitor.Volatility})
+	time.Sleep(10 * time.Millisecond) // let a tick in flight finish
+
+	return client, rates
+}
+
+// subscribePairs opens a stream subscribed to EUR against dests and returns its resume token
+func subscribePairs(t *testing.T, client protos.CurrencyClient, dests ...string) string {
+	t.Helper()
+
+	stream, err := client.SubscribeRates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pairs := make([]*protos.RateRequest, 0, len(dests))
+	for _, dest := range dests {
+		pairs = append(pairs, &protos.RateRequest{BaseCode: "EUR", DestinationCode: dest})
+	}
+	err = stream.Send(&protos.SubscriptionRequest{
+		Operation: &protos.SubscriptionRequest_Replace{Replace: &protos.ReplaceSubscriptions{Pairs: pairs}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ack := msg.GetAck(); ack != nil {
+			token := ack.GetResumeToken()
+
+			// the subscriptions are detached once the server closed the stream
+			if err := stream.CloseSend(); err != nil {
+				t.Fatal(err)
+			}
+			for err == nil {
+				_, err = stream.Recv()
+			}
+			return token
+		}
+	}
+}
+
+// resumeStream resumes token on a new stream and returns the ack and the updates
+// received until the stream goes quiet, err is set when the stream is closed
+func resumeStream(t *testing.T, client protos.CurrencyClient, token string, lastSequence uint64) (*protos.SubscriptionAck, []*protos.RateResponse, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	stream, err := client.SubscribeRates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&protos.SubscriptionRequest{
+		Operation: &protos.SubscriptionRequest_Resume{Resume: &protos.Resume{Token: token, LastSequence: lastSequence}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		msg *protos.StreamingRateResponse
+		err error
+	}
+	results := make(chan result)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			results <- result{msg, err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var ack *protos.SubscriptionAck
+	var updates []*protos.RateResponse
+	for {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				return ack, updates, r.err
+			}
+			if a := r.msg.GetAck(); a != nil {
+				ack = a
+			}
+			if rr := r.msg.GetRateResponse(); rr != nil {
+				updates = append(updates, rr)
+			}
+		case <-time.After(1500 * time.Millisecond):
+			// long enough for a silence tick to push deferred pairs
+			return ack, updates, nil
+		}
+	}
+}
+
+func TestResumeReplaysMissedUpdates(t *testing.T) {
+	client, rates := newResumeServer(t, StreamOptions{QueueSize: 8, Overflow: OverflowDisconnect}, 5)
+	token := subscribePairs(t, client, "USD", "GBP")
+
+	// two snapshots with two pairs each fit next to the ack
+	ack, updates, err := resumeStream(t, client, token, rates.Sequence()-2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack == nil || ack.GetResync() {
+		t.Fatalf("expected a resume without resync, got %v", ack)
+	}
+	if len(updates) != 4 {
+		t.Errorf("expected 4 replayed updates, got %d", len(updates))
+	}
+}
+
+func TestResumeReplayFillingTheQueue(t *testing.T) {
+	const queueSize = 4
+	client, rates := newResumeServer(t, StreamOptions{QueueSize: queueSize, Overflow: OverflowDisconnect}, 5)
+	token := subscribePairs(t, client, "USD", "GBP")
+
+	// exactly queueSize missed updates leave no room for the ack, the client resyncs
+	ack, updates, err := resumeStream(t, client, token, rates.Sequence()-queueSize/2)
+	if err != nil {
+		t.Fatalf("expected the stream to stay open, got %v", err)
+	}
+	if ack == nil || !ack.GetResync() {
+		t.Fatalf("expected a resync, got %v", ack)
+	}
+	if len(updates) != 2 {
+		t.Errorf("expected the current rate of both pairs, got %d updates", len(updates))
+	}
+	for _, rr := range updates {
+		if rr.GetSequence() != rates.Sequence() {
+			t.Errorf("expected the current sequence %d, got %d", rates.Sequence(), rr.GetSequence())
+		}
+	}
+}
+
+func TestResumeResyncLargerThanTheQueue(t *testing.T) {
+	client, rates := newResumeServer(t, StreamOptions{QueueSize: 3, Overflow: OverflowDisconnect}, 2)
+	token := subscribePairs(t, client, "USD", "GBP", "JPY", "CHF", "AUD")
+
+	// five pairs do not fit next to the ack, the rest follow on the next silence tick
+	ack, updates, err := resumeStream(t, client, token, 0)
+	if err != nil {
+		t.Fatalf("expected the stream to stay open, got %v", err)
+	}
+	if ack == nil || !ack.GetResync() {
+		t.Fatalf("expected a resync, got %v", ack)
+	}
+
+	seen := make(map[string]bool)
+	for _, rr := range updates {
+		seen[rr.GetDestinationCode()] = true
+		if rr.GetSequence() != rates.Sequence() {
+			t.Errorf("expected the current sequence %d, got %d", rates.Sequence(), rr.GetSequence())
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected the current rate of all 5 pairs, got %v", seen)
+	}
+}
@@ -0,0 +1,199 @@
+package server
+
+import (
+	"fmt"
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what happens when a subscriber's outbound queue is full
+type OverflowPolicy string
+
+const (
+	// OverflowCoalesce replaces a queued update for the same pair with the latest
+	// value, or drops the oldest queued update when the pair has none queued
+	OverflowCoalesce OverflowPolicy = "coalesce"
+	// OverflowDisconnect closes the stream of a subscriber that cannot keep up
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+// ParseOverflowPolicy converts a configuration value into an OverflowPolicy
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(s); p {
+	case OverflowCoalesce, OverflowDisconnect:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy %q, expected %q or %q", s, OverflowCoalesce, OverflowDisconnect)
+	}
+}
+
+// StreamOptions configures the outbound queue of every SubscribeRates stream
+type StreamOptions struct {
+	// QueueSize is the number of messages buffered for a subscriber before Overflow applies
+	QueueSize int
+	// Overflow is the policy applied when the queue is full
+	Overflow OverflowPolicy
+}
+
+// DefaultStreamOptions returns a queue of 64 messages that coalesces on overflow
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		QueueSize: 64,
+		Overflow:  OverflowCoalesce,
+	}
+}
+
+// StreamStats are totals about the updates pushed to subscribers since the server started
+type StreamStats struct {
+	// Dropped counts updates discarded without being delivered
+	Dropped uint64
+	// Coalesced counts updates replaced by a newer value for the same pair
+	Coalesced uint64
+	// Sent counts updates written to a stream
+	Sent uint64
+	// SendFailures counts updates that could not be written, each closes its stream
+	SendFailures uint64
+	// StaleRemoved counts subscriptions removed for inactivity
+	StaleRemoved uint64
+}
+
+// streamCounters accumulates StreamStats across all subscribers
+type streamCounters struct {
+	dropped      atomic.Uint64
+	coalesced    atomic.Uint64
+	sent         atomic.Uint64
+	sendFailures atomic.Uint64
+	staleRemoved atomic.Uint64
+}
+
+// outboundItem is a queued message, pair is empty for acks and errors
+type outboundItem struct {
+	pair string
+	msg  *protos.StreamingRateResponse
+}
+
+// outboundQueue is the bounded queue between the server and the writer of a
+// single stream. Pushing never blocks, so a slow client only affects itself.
+type outboundQueue struct {
+	opts     StreamOptions
+	counters *streamCounters
+
+	mutex      sync.Mutex
+	items      []outboundItem
+	dropped    uint64
+	coalesced  uint64
+	overflowed bool // set when the stream has to be disconnected
+
+	ready chan struct{} // signalled whenever items are queued
+}
+
+func newOutboundQueue(opts StreamOptions, counters *streamCounters) *outboundQueue {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultStreamOptions().QueueSize
+	}
+	return &outboundQueue{
+		opts:     opts,
+		counters: counters,
+		ready:    make(chan struct{}, 1),
+	}
+}
+
+// pushRate queues a rate update for pair, applying the overflow policy when the queue is full
+func (q *outboundQueue) pushRate(pair string, msg *protos.StreamingRateResponse) {
+	q.push(outboundItem{pair: pair, msg: msg})
+}
+
+// pushControl queues an ack or error, these are never coalesced
+func (q *outboundQueue) pushControl(msg *protos.StreamingRateResponse) {
+	q.push(outboundItem{msg: msg})
+}
+
+func (q *outboundQueue) push(item outboundItem) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.overflowed {
+		return
+	}
+
+	if len(q.items) < q.opts.QueueSize {
+		q.items = append(q.items, item)
+	} else if !q.makeRoom(item) {
+		return
+	}
+
+	q.signal()
+}
+
+// makeRoom applies the overflow policy to a full queue and queues item if it
+// still can, it reports whether the writer needs to be woken up
+func (q *outboundQueue) makeRoom(item outboundItem) bool {
+	if q.opts.Overflow == OverflowCoalesce {
+		// replace the queued update for the same pair with the latest value
+		if item.pair != "" {
+			for i := range q.items {
+				if q.items[i].pair == item.pair {
+					q.items[i].msg = item.msg
+					q.coalesced++
+					q.counters.coalesced.Add(1)
+					return false
+				}
+			}
+		}
+
+		// otherwise drop the oldest queued update, acks and errors are kept
+		for i := range q.items {
+			if q.items[i].pair != "" {
+				q.items = append(q.items[:i:i], q.items[i+1:]...)
+				q.items = append(q.items, item)
+				q.dropped++
+				q.counters.dropped.Add(1)
+				return true
+			}
+		}
+	}
+
+	// the queue cannot make room, give up on the subscriber
+	q.overflowed = true
+	q.dropped += uint64(len(q.items)) + 1
+	q.counters.dropped.Add(uint64(len(q.items)) + 1)
+	q.items = nil
+	return true
+}
+
+// free returns the number of messages that can be queued before the overflow policy applies
+func (q *outboundQueue) free() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.opts.QueueSize - len(q.items)
+}
+
+func (q *outboundQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// drain removes and returns all queued messages, and whether the stream overflowed
+func (q *outboundQueue) drain() ([]*protos.StreamingRateResponse, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	msgs := make([]*protos.StreamingRateResponse, len(q.items))
+	for i, item := range q.items {
+		msgs[i] = item.msg
+	}
+	q.items = nil
+	return msgs, q.overflowed
+}
+
+// stats returns the per stream counters
+func (q *outboundQueue) stats() StreamStats {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return StreamStats{Dropped: q.dropped, Coalesced: q.coalesced}
+}
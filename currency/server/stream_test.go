@@ -0,0 +1,168 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+)
+
+// update creates a rate update for pair carrying sequence as its rate so messages can be told apart
+func update(pair string, sequence uint64) outboundItem {
+	return outboundItem{pair: pair, msg: &protos.StreamingRateResponse{
+		Message: &protos.StreamingRateResponse_RateResponse{
+			RateResponse: &protos.RateResponse{BaseCode: pair[:3], DestinationCode: pair[4:], ExactRate: fmt.Sprint(sequence)},
+		},
+	}}
+}
+
+func ack(requestID uint64) *protos.StreamingRateResponse {
+	return &protos.StreamingRateResponse{
+		Message: &protos.StreamingRateResponse_Ack{Ack: &protos.SubscriptionAck{RequestId: requestID}},
+	}
+}
+
+// describe summarises queued messages as pair@sequence for updates and ack#id for acks
+func describe(msgs []*protos.StreamingRateResponse) []string {
+	var out []string
+	for _, msg := range msgs {
+		if rr := msg.GetRateResponse(); rr != nil {
+			out = append(out, fmt.Sprintf("%s/%s@%s", rr.GetBaseCode(), rr.GetDestinationCode(), rr.GetExactRate()))
+		} else {
+			out = append(out, fmt.Sprintf("ack#%d", msg.GetAck().GetRequestId()))
+		}
+	}
+	return out
+}
+
+func TestOutboundQueueCoalesce(t *testing.T) {
+	tests := []struct {
+		name      string
+		push      func(q *outboundQueue)
+		expected  []string
+		dropped   uint64
+		coalesced uint64
+	}{
+		{
+			name: "below capacity",
+			push: func(q *outboundQueue) {
+				q.push(update("EUR/USD", 1))
+				q.pushControl(ack(1))
+			},
+			expected: []string{"EUR/USD@1", "ack#1"},
+		},
+		{
+			name: "replaces the queued update of the same pair",
+			push: func(q *outboundQueue) {
+				q.push(update("EUR/USD", 1))
+				q.push(update("EUR/GBP", 1))
+				q.push(update("EUR/JPY", 1))
+				q.push(update("EUR/GBP", 2))
+			},
+			expected:  []string{"EUR/USD@1", "EUR/GBP@2", "EUR/JPY@1"},
+			coalesced: 1,
+		},
+		{
+			name: "drops the oldest update of another pair",
+			push: func(q *outboundQueue) {
+				q.pushControl(ack(1))
+				q.push(update("EUR/USD", 1))
+				q.push(update("EUR/GBP", 1))
+				q.push(update("EUR/JPY", 1))
+			},
+			expected: []string{"ack#1", "EUR/GBP@1", "EUR/JPY@1"},
+			dropped:  1,
+		},
+		{
+			name: "overflows when only acks are queued",
+			push: func(q *outboundQueue) {
+				q.pushControl(ack(1))
+				q.pushControl(ack(2))
+				q.pushControl(ack(3))
+				q.pushControl(ack(4))
+				q.push(update("EUR/USD", 1))
+			},
+			expected: nil,
+			dropped:  4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counters := &streamCounters{}
+			q := newOutboundQueue(StreamOptions{QueueSize: 3, Overflow: OverflowCoalesce}, counters)
+			tt.push(q)
+
+			msgs, overflowed := q.drain()
+			if got := describe(msgs); !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if overflowed != (tt.expected == nil) {
+				t.Errorf("expected overflowed %v, got %v", tt.expected == nil, overflowed)
+			}
+
+			stats := q.stats()
+			if stats.Dropped != tt.dropped || stats.Coalesced != tt.coalesced {
+				t.Errorf("expected %d dropped and %d coalesced, got %+v", tt.dropped, tt.coalesced, stats)
+			}
+			if counters.dropped.Load() != tt.dropped || counters.coalesced.Load() != tt.coalesced {
+				t.Errorf("expected the shared counters to match the queue, got %d dropped and %d coalesced",
+					counters.dropped.Load(), counters.coalesced.Load())
+			}
+		})
+	}
+}
+
+func TestOutboundQueueDisconnect(t *testing.T) {
+	counters := &streamCounters{}
+	q := newOutboundQueue(StreamOptions{QueueSize: 2, Overflow: OverflowDisconnect}, counters)
+
+	q.push(update("EUR/USD", 1))
+	q.push(update("EUR/USD", 2))
+	if msgs, overflowed := q.drain(); overflowed || len(msgs) != 2 {
+		t.Fatalf("expected 2 queued updates without overflow, got %d (%v)", len(msgs), overflowed)
+	}
+
+	// the same pair is not coalesced, the subscriber is disconnected instead
+	q.push(update("EUR/USD", 3))
+	q.push(update("EUR/USD", 4))
+	q.push(update("EUR/USD", 5))
+	msgs, overflowed := q.drain()
+	if !overflowed || len(msgs) != 0 {
+		t.Fatalf("expected an empty overflowed queue, got %d (%v)", len(msgs), overflowed)
+	}
+	if q.stats().Dropped != 3 || counters.dropped.Load() != 3 {
+		t.Errorf("expected 3 dropped updates, got %+v", q.stats())
+	}
+
+	// nothing is queued once the stream is to be disconnected
+	q.pushControl(ack(1))
+	if msgs, overflowed := q.drain(); !overflowed || len(msgs) != 0 {
+		t.Errorf("expected the queue to stay empty after overflowing, got %d (%v)", len(msgs), overflowed)
+	}
+}
+
+func TestOutboundQueueSignalsWriter(t *testing.T) {
+	q := newOutboundQueue(StreamOptions{QueueSize: 1, Overflow: OverflowCoalesce}, &streamCounters{})
+
+	q.push(update("EUR/USD", 1))
+	select {
+	case <-q.ready:
+	default:
+		t.Fatal("expected the writer to be signalled when an update is queued")
+	}
+
+	// coalescing replaces a message the writer already knows about
+	q.push(update("EUR/USD", 2))
+	select {
+	case <-q.ready:
+		t.Error("expected no signal when an update was coalesced")
+	default:
+	}
+
+	msgs, _ := q.drain()
+	if got := describe(msgs); !slices.Equal(got, []string{"EUR/USD@2"}) {
+		t.Errorf("expected the latest value, got %v", got)
+	}
+}
@@ -0,0 +1,272 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Options names the PEM files of a TLS endpoint
+type Options struct {
+	// CertFile and KeyFile are the certificate and key presented to the peer,
+	// they are optional for clients
+	CertFile string
+	KeyFile  string
+	// CAFile holds the certificates used to verify the peer. For a server it
+	// enables client certificate verification, for a client it replaces the
+	// system roots.
+	CAFile string
+	// RequireClientCert rejects clients without a valid certificate, it needs CAFile
+	RequireClientCert bool
+	// ServerName is the name a client expects in the server certificate,
+	// it defaults to the host of the dialled address
+	ServerName string
+	// ReloadInterval is how often the files are checked for changes
+	ReloadInterval time.Duration
+}
+
+// DefaultReloadInterval is used when Options.ReloadInterval is not set
+const DefaultReloadInterval = 30 * time.Second
+
+// Validate checks the combination of files is usable
+func (o Options) Validate() error {
+	var errs []error
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		errs = append(errs, errors.New("certificate and key files must be set together"))
+	}
+	if o.RequireClientCert && o.CAFile == "" {
+		errs = append(errs, errors.New("requiring client certificates needs a CA file"))
+	}
+	return errors.Join(errs...)
+}
+
+// Reloader holds the certificates of Options and reloads them when the files change,
+// TLS configurations created from it always use the latest certificates so rotated
+// files are picked up without a restart
+type Reloader struct {
+	log  hclog.Logger
+	opts Options
+
+	mutex   sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	modTime time.Time // latest modification time of the loaded files
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewReloader loads the files of opts and starts watching them for changes
+func NewReloader(l hclog.Logger, opts Options) (*Reloader, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.ReloadInterval <= 0 {
+		opts.ReloadInterval = DefaultReloadInterval
+	}
+
+	r := &Reloader{
+		log:     l,
+		opts:    opts,
+		closeCh: make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	r.wg.Add(1)
+	go r.watch()
+
+	return r, nil
+}
+
+// load reads the certificate, key and CA files
+func (r *Reloader) load() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if r.opts.CertFile != "" {
+		c, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+		if err != nil {
+			return fmt.Errorf("unable to load certificate: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.opts.CAFile != "" {
+		pem, err := os.ReadFile(r.opts.CAFile)
+		if err != nil {
+			return fmt.Errorf("unable to read CA file: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA file %s", r.opts.CAFile)
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cert = cert
+	r.pool = pool
+	r.modTime = modTime
+	return nil
+}
+
+// latestModTime returns the most recent modification time of the configured files
+func (r *Reloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.opts.CertFile, r.opts.KeyFile, r.opts.CAFile} {
+		if path == "" {
+			continue
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// watch reloads the files whenever one of them changed, a failed reload keeps
+// the certificates that are already loaded
+func (r *Reloader) watch() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.opts.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			modTime, err := r.latestModTime()
+			if err != nil {
+				r.log.Error("Unable to check TLS files", "error", err)
+				continue
+			}
+
+			r.mutex.RLock()
+			changed := !modTime.Equal(r.modTime)
+			r.mutex.RUnlock()
+			if !changed {
+				continue
+			}
+
+			if err := r.load(); err != nil {
+				r.log.Error("Unable to reload TLS files, keeping the current certificates", "error", err)
+				continue
+			}
+			r.log.Info("Reloaded TLS certificates")
+		case <-r.closeCh:
+			return
+		}
+	}
+}
+
+func (r *Reloader) current() (*tls.Certificate, *x509.CertPool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.cert, r.pool
+}
+
+// ServerConfig returns the TLS configuration of a server. Client certificates are
+// verified when a CA file is configured and required if RequireClientCert is set.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			cert, pool := r.current()
+			if cert == nil {
+				return nil, errors.New("no server certificate loaded")
+			}
+
+			// the per connection config replaces the one gRPC added its ALPN
+			// protocol to, so HTTP/2 has to be offered here as well
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*cert},
+				NextProtos:   []string{"h2"},
+			}
+			if pool != nil {
+				cfg.ClientCAs = pool
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				if r.opts.RequireClientCert {
+					cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// ClientConfig returns the TLS configuration of a client. The server certificate
+// is verified against the current CA pool, or the system roots without a CA file,
+// and the client certificate is presented when one is configured.
+func (r *Reloader) ClientConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: r.opts.ServerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, _ := r.current()
+			if cert == nil {
+				// no certificate, the server decides whether that is acceptable
+				return &tls.Certificate{}, nil
+			}
+			return cert, nil
+		},
+	}
+
+	if r.opts.CAFile != "" {
+		// the standard verification can only use a fixed pool, verify against
+		// the current pool instead so a rotated CA is picked up
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			_, pool := r.current()
+			return verifyServer(cs, pool)
+		}
+	}
+	return cfg
+}
+
+// verifyServer performs the verification crypto/tls does by default against pool
+func verifyServer(cs tls.ConnectionState, pool *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	if cs.ServerName == "" {
+		return errors.New("server name is unknown, unable to verify the server certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       cs.ServerName,
+		Roots:         pool,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// Close stops watching the files
+func (r *Reloader) Close() {
+	r.once.Do(func() {
+		close(r.closeCh)
+		r.wg.Wait()
+	})
+}
@@ -0,0 +1,130 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kahvecikaan/buildingMicroservices/currency/tlsconfig/tlstest"
+)
+
+// writeCerts mints a new CA and writes a server and a client certificate to dir,
+// generation sets the modification time so every generation is seen as a change
+func writeCerts(t *testing.T, dir string, generation int) {
+	t.Helper()
+
+	ca, err := tlstest.NewCA("test-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"server", "client"} {
+		if _, _, err := ca.WriteFiles(dir, name, "localhost", "127.0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// make sure the change is visible even on file systems with coarse timestamps
+	future := time.Now().Add(time.Duration(generation) * time.Minute)
+	for _, f := range []string{"ca.crt", "server.crt", "server.key", "client.crt", "client.key"} {
+		if err := os.Chtimes(filepath.Join(dir, f), future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// handshake connects client to server over a local listener and returns the error seen by the client
+func handshake(t *testing.T, server, client *tls.Config) error {
+	t.Helper()
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = conn.Write([]byte("ok"))
+	}()
+
+	conn, err := tls.Dial("tcp", lis.Addr().String(), client)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// with TLS 1.3 a rejected client certificate only surfaces on the first read
+	_, err = io.ReadAll(conn)
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	writeCerts(t, dir, 0)
+
+	server, err := NewReloader(hclog.NewNullLogger(), Options{
+		CertFile:          filepath.Join(dir, "server.crt"),
+		KeyFile:           filepath.Join(dir, "server.key"),
+		CAFile:            filepath.Join(dir, "ca.crt"),
+		RequireClientCert: true,
+		ReloadInterval:    10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	client, err := NewReloader(hclog.NewNullLogger(), Options{
+		CertFile:       filepath.Join(dir, "client.crt"),
+		KeyFile:        filepath.Join(dir, "client.key"),
+		CAFile:         filepath.Join(dir, "ca.crt"),
+		ServerName:     "localhost",
+		ReloadInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	if err := handshake(t, server.ServerConfig(), client.ClientConfig()); err != nil {
+		t.Fatalf("expected the handshake to succeed, got %v", err)
+	}
+
+	anonymous, err := NewReloader(hclog.NewNullLogger(), Options{
+		CAFile:     filepath.Join(dir, "ca.crt"),
+		ServerName: "localhost",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anonymous.Close()
+
+	if err := handshake(t, server.ServerConfig(), anonymous.ClientConfig()); err == nil {
+		t.Error("expected a client without certificate to be rejected")
+	}
+
+	// rotate every file to a new CA, both sides pick it up without being recreated
+	writeCerts(t, dir, 1)
+	time.Sleep(100 * time.Millisecond)
+
+	if err := handshake(t, server.ServerConfig(), client.ClientConfig()); err != nil {
+		t.Fatalf("expected the handshake to succeed after rotation, got %v", err)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	if err := (Options{CertFile: "server.crt"}).Validate(); err == nil {
+		t.Error("expected a certificate without key to be rejected")
+	}
+	if err := (Options{RequireClientCert: true}).Validate(); err == nil {
+		t.Error("expected requiring client certificates without CA to be rejected")
+	}
+}
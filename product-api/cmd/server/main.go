@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/go-hclog"
 	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"github.com/kahvecikaan/buildingMicroservices/currency/tlsconfig"
 	"github.com/kahvecikaan/buildingMicroservices/product-api/internal/domain"
 	"github.com/kahvecikaan/buildingMicroservices/product-api/internal/events"
 	"github.com/kahvecikaan/buildingMicroservices/product-api/internal/repository"
@@ -12,7 +14,11 @@ import (
 	websocketTransport "github.com/kahvecikaan/buildingMicroservices/product-api/internal/transport/websocket"
 	"github.com/nicholasjackson/env"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	"net/http"
 	"os"
 	"os/signal"
@@ -28,6 +34,16 @@ var (
 		"debug", "Log output level for the server [debug, info, trace]")
 	grpcAddress = env.String("GRPC_ADDRESS", false,
 		":9092", "Address of the gRPC currency service")
+	grpcCAFile = env.String("GRPC_TLS_CA_FILE", false,
+		"", "CA certificate verifying the currency service, enables TLS")
+	grpcCertFile = env.String("GRPC_TLS_CERT_FILE", false,
+		"", "Client certificate presented to the currency service, enables TLS")
+	grpcKeyFile = env.String("GRPC_TLS_KEY_FILE", false,
+		"", "Key of the client certificate")
+	grpcServerName = env.String("GRPC_TLS_SERVER_NAME", false,
+		"", "Name expected in the certificate of the currency service, defaults to the host of GRPC_ADDRESS")
+	currencyAPIKey = env.String("CURRENCY_API_KEY", false,
+		"", "API key sent to the currency service")
 )
 
 func main() {
@@ -46,9 +62,28 @@ func main() {
 	// Initialize the event bus - this will be shared between services
 	eventBus := events.NewEventBus[any]()
 
-	// Set up the currency gRPC client
+	// Set up the currency gRPC client, over TLS when a CA or client certificate is configured
+	creds := insecure.NewCredentials()
+	if *grpcCAFile != "" || *grpcCertFile != "" {
+		certs, err := tlsconfig.NewReloader(logger, tlsconfig.Options{
+			CertFile:   *grpcCertFile,
+			KeyFile:    *grpcKeyFile,
+			CAFile:     *grpcCAFile,
+			ServerName: *grpcServerName,
+		})
+		if err != nil {
+			logger.Error("Unable to load TLS certificates for the currency service", "error", err)
+			os.Exit(1)
+		}
+		defer certs.Close()
+
+		creds = credentials.NewTLS(certs.ClientConfig())
+	}
 	dialOpts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
+	}
+	if *currencyAPIKey != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(apiKey(*currencyAPIKey)))
 	}
 	grpcConn, err := grpc.NewClient(*grpcAddress, dialOpts...)
 	if err != nil {
@@ -60,7 +95,7 @@ func main() {
 	currencyClient := protos.NewCurrencyClient(grpcConn)
 
 	// Check if the currency service is available
-	if err := checkCurrencyService(currencyClient); err != nil {
+	if err := checkCurrencyService(grpcConn, currencyClient); err != nil {
 		logger.Error("Currency service is not available", "error", err)
 		os.Exit(1)
 	}
@@ -144,14 +179,41 @@ func main() {
 	logger.Info("Server shutdown complete.")
 }
 
-func checkCurrencyService(client protos.CurrencyClient) error {
+// checkCurrencyService asks the currency service whether it is serving, servers
+// without the health service are probed with a GetRate request instead
+func checkCurrencyService(conn grpc.ClientConnInterface, client protos.CurrencyClient) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := client.GetRate(ctx, &protos.RateRequest{
-		Base:        protos.Currencies_EUR,
-		Destination: protos.Currencies_USD,
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: protos.Currency_ServiceDesc.ServiceName,
 	})
+	if status.Code(err) == codes.Unimplemented {
+		_, err = client.GetRate(ctx, &protos.RateRequest{
+			BaseCode:        "EUR",
+			DestinationCode: "USD",
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("currency service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
+// apiKey sends an API key with every call to the currency service
+type apiKey string
+
+func (k apiKey) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"x-api-key": string(k)}, nil
+}
 
-	return err
+// RequireTransportSecurity allows the key over plaintext connections, the currency
+// service may run without TLS inside a trusted network
+func (k apiKey) RequireTransportSecurity() bool {
+	return false
 }
@@ -4,7 +4,10 @@ import (
 	"context"
 	"github.com/hashicorp/go-hclog"
 	"github.com/kahvecikaan/buildingMicroservices/currency/protos"
+	"github.com/kahvecikaan/buildingMicroservices/product-api/internal/domain"
 	"github.com/kahvecikaan/buildingMicroservices/product-api/internal/events"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"sync"
 	"time"
@@ -21,8 +24,12 @@ type currencyService struct {
 	log           hclog.Logger
 	client        protos.CurrencyClient
 	rates         map[string]float64
+	sequences     map[string]uint64 // snapshot sequence of each rate, older updates are ignored
 	ratesMutex    sync.RWMutex
 	stream        protos.Currency_SubscribeRatesClient
+	sendMutex     sync.Mutex // a stream allows one sender at a time, see send
+	resumeToken   string     // identifies our subscriptions on the server across reconnects
+	lastSequence  uint64     // highest snapshot sequence received on the stream
 	subscriptions map[string]struct{}
 	subMutex      sync.RWMutex
 	closeCh       chan struct{}
@@ -39,6 +46,7 @@ func NewCurrencyService(
 		log:           logger,
 		client:        client,
 		rates:         make(map[string]float64),
+		sequences:     make(map[string]uint64),
 		subscriptions: make(map[string]struct{}),
 		closeCh:       make(chan struct{}),
 		eventBus:      eventBus,
@@ -64,9 +72,72 @@ func (s *currencyService) initializeStream(ctx context.Context) error {
 		return err
 	}
 	s.stream = stream
+
+	// pick up where the previous stream stopped
+	s.ratesMutex.RLock()
+	token, lastSequence := s.resumeToken, s.lastSequence
+	s.ratesMutex.RUnlock()
+
+	if token == "" {
+		s.resetSequences()
+		return s.resubscribe(stream)
+	}
+
+	s.log.Debug("Resuming rate subscriptions", "last_sequence", lastSequence)
+	err = s.send(stream, &protos.SubscriptionRequest{
+		Operation: &protos.SubscriptionRequest_Resume{
+			Resume: &protos.Resume{
+				Token:        token,
+				LastSequence: lastSequence,
+			},
+		},
+	})
+	if err != nil {
+		s.log.Error("Error sending resume request", "error", err)
+		return err
+	}
+	return nil
+}
+
+// resubscribe subscribes stream to all currencies we were subscribed to,
+// it is used when the previous subscriptions cannot be resumed
+func (s *currencyService) resubscribe(stream protos.Currency_SubscribeRatesClient) error {
+	s.subMutex.RLock()
+	pairs := make([]*protos.RateRequest, 0, len(s.subscriptions))
+	for currency := range s.subscriptions {
+		pairs = append(pairs, &protos.RateRequest{
+			BaseCode:        "EUR",
+			DestinationCode: currency,
+		})
+	}
+	s.subMutex.RUnlock()
+
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	s.log.Debug("Re-sending rate subscriptions", "pairs", len(pairs))
+	err := s.send(stream, &protos.SubscriptionRequest{
+		Operation: &protos.SubscriptionRequest_Replace{
+			Replace: &protos.ReplaceSubscriptions{Pairs: pairs},
+		},
+	})
+	if err != nil {
+		s.log.Error("Error re-sending rate subscriptions", "error", err)
+		return err
+	}
 	return nil
 }
 
+// send writes req to stream. The heartbeat, SubscribeToRates and the receive loop
+// all send on the same stream, which must not happen concurrently.
+func (s *currencyService) send(stream protos.Currency_SubscribeRatesClient, req *protos.SubscriptionRequest) error {
+	s.sendMutex.Lock()
+	defer s.sendMutex.Unlock()
+
+	return stream.Send(req)
+}
+
 func (s *currencyService) handleHeartbeat() {
 	defer s.wg.Done()
 	ticker := time.NewTimer(1 * time.Minute)
@@ -81,12 +152,13 @@ func (s *currencyService) handleHeartbeat() {
 					continue
 				}
 			}
-			heartbeat := &protos.RateRequest{
-				Base:        protos.Currencies_UNKNOWN,
-				Destination: protos.Currencies_UNKNOWN,
+			heartbeat := &protos.SubscriptionRequest{
+				Operation: &protos.SubscriptionRequest_Heartbeat{
+					Heartbeat: &protos.Heartbeat{},
+				},
 			}
 
-			if err := s.stream.Send(heartbeat); err != nil {
+			if err := s.send(s.stream, heartbeat); err != nil {
 				s.log.Error("Failed to send heartbeat", "error", err)
 				// Attempt to reinitialize the stream
 				_ = s.initializeStream(context.Background())
@@ -125,17 +197,29 @@ func (s *currencyService) handleRateUpdates() {
 
 			switch msg := response.Message.(type) {
 			case *protos.StreamingRateResponse_RateResponse:
-				currency := msg.RateResponse.GetDestination().String()
+				currency := msg.RateResponse.GetDestinationCode()
+				if currency == "" {
+					// older servers only set the enum
+					currency = msg.RateResponse.GetDestination().String()
+				}
 				newRate := msg.RateResponse.GetRate()
+				sequence := msg.RateResponse.GetSequence()
 
 				s.ratesMutex.Lock()
+				if sequence < s.sequences[currency] {
+					// replayed or reordered update older than the rate we have
+					s.ratesMutex.Unlock()
+					continue
+				}
 				oldRate, exists := s.rates[currency]
 				s.rates[currency] = newRate
+				s.sequences[currency] = sequence
+				s.lastSequence = max(s.lastSequence, sequence)
 				s.ratesMutex.Unlock()
 
 				s.log.Debug(
 					"Updated rate",
-					"destination", msg.RateResponse.GetDestination().String(),
+					"destination", currency,
 					"rate", msg.RateResponse.GetRate())
 
 				// Only publish the event if rate actually changed
@@ -148,11 +232,55 @@ func (s *currencyService) handleRateUpdates() {
 				}
 			case *protos.StreamingRateResponse_Error:
 				s.log.Error("Received error from server", "error", msg.Error.GetMessage())
+				if isResumeError(msg.Error) {
+					// the server no longer has our subscriptions, it may have restarted
+					// and numbers its snapshots from 1 again, start over
+					s.ratesMutex.Lock()
+					s.resumeToken = ""
+					s.ratesMutex.Unlock()
+					s.resetSequences()
+					_ = s.resubscribe(s.stream)
+				}
+			case *protos.StreamingRateResponse_Ack:
+				s.log.Debug(
+					"Subscription request acknowledged",
+					"operation", msg.Ack.GetOperation().String(),
+					"subscriptions", len(msg.Ack.GetSubscriptions()),
+					"resync", msg.Ack.GetResync())
+
+				s.ratesMutex.Lock()
+				s.resumeToken = msg.Ack.GetResumeToken()
+				s.ratesMutex.Unlock()
+				if msg.Ack.GetResync() {
+					// the current rates follow, they replace whatever we had
+					s.resetSequences()
+				}
 			}
 		}
 	}
 }
 
+// resetSequences forgets the snapshot sequences seen so far, they are only
+// comparable within the subscriptions of a single resume token
+func (s *currencyService) resetSequences() {
+	s.ratesMutex.Lock()
+	defer s.ratesMutex.Unlock()
+
+	s.sequences = make(map[string]uint64)
+	s.lastSequence = 0
+}
+
+// isResumeError reports whether a stream error answers a resume request
+func isResumeError(st *rpcstatus.Status) bool {
+	for _, detail := range st.GetDetails() {
+		req := &protos.SubscriptionRequest{}
+		if detail.UnmarshalTo(req) == nil && req.GetResume() != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *currencyService) GetRate(ctx context.Context, base, destination string) (float64, error) {
 	s.log.Debug("Getting exchange rate", "base", base, "destination", destination)
 
@@ -166,8 +294,8 @@ func (s *currencyService) GetRate(ctx context.Context, base, destination string)
 
 	// Request new rate via gRPC call
 	rateRequest := &protos.RateRequest{
-		Base:        protos.Currencies(protos.Currencies_value[base]),
-		Destination: protos.Currencies(protos.Currencies_value[destination]),
+		BaseCode:        base,
+		DestinationCode: destination,
 	}
 
 	resp, err := s.client.GetRate(ctx, rateRequest)
@@ -178,6 +306,9 @@ func (s *currencyService) GetRate(ctx context.Context, base, destination string)
 			"base", base,
 			"destination", destination,
 			"error", grpcErr.Message())
+		if grpcErr.Code() == codes.InvalidArgument {
+			return 0, domain.ErrInvalidCurrency
+		}
 		return 0, err
 	}
 
@@ -210,12 +341,16 @@ func (s *currencyService) SubscribeToRates(ctx context.Context, currencies []str
 		}
 		s.subscriptions[currency] = struct{}{}
 
-		rateRequest := &protos.RateRequest{
-			Base:        protos.Currencies(protos.Currencies_value["EUR"]),
-			Destination: protos.Currencies(protos.Currencies_value[currency]),
+		rateRequest := &protos.SubscriptionRequest{
+			Operation: &protos.SubscriptionRequest_Subscribe{
+				Subscribe: &protos.RateRequest{
+					BaseCode:        "EUR",
+					DestinationCode: currency,
+				},
+			},
 		}
 
-		if err := s.stream.Send(rateRequest); err != nil {
+		if err := s.send(s.stream, rateRequest); err != nil {
 			s.log.Error("Error sending rate subscription request", "currency", currency, "error", err)
 			s.subMutex.Unlock()
 			return err
@@ -248,7 +383,9 @@ func (s *currencyService) Close() error {
 
 		// Close the gRPC stream
 		if s.stream != nil {
+			s.sendMutex.Lock()
 			err = s.stream.CloseSend()
+			s.sendMutex.Unlock()
 		}
 
 		// Wait for all goroutines to finish